@@ -0,0 +1,56 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Mean returns the mean of xs.  It can be used as a Stream.AggregateFunc.
+func Mean(xs []float64) float64 {
+	var sum float64
+	for _, v := range xs {
+		sum += v
+	}
+	return sum / float64(len(xs))
+}
+
+// Max returns the largest element of xs.  It can be used as a Stream.AggregateFunc.
+func Max(xs []float64) float64 {
+	m := math.Inf(-1)
+	for _, v := range xs {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
+
+// Min returns the smallest element of xs.  It can be used as a Stream.AggregateFunc.
+func Min(xs []float64) float64 {
+	m := math.Inf(1)
+	for _, v := range xs {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// Quantile returns a Stream.AggregateFunc computing the q-th quantile, 0 <= q
+// <= 1, of its argument using the nearest-rank method.  The argument is
+// sorted in place.
+func Quantile(q float64) func([]float64) float64 {
+	return func(xs []float64) float64 {
+		sort.Float64s(xs)
+		return nearestRank(xs, q)
+	}
+}
+
+// nearestRank returns the q-th quantile of the sorted slice xs
+func nearestRank(xs []float64, q float64) float64 {
+	idx := int(math.Ceil(q*float64(len(xs)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return xs[idx]
+}
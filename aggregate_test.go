@@ -0,0 +1,53 @@
+package change
+
+import "testing"
+
+func TestAggregates(t *testing.T) {
+
+	xs := []float64{3, 1, 4, 1, 5, 9, 2, 6, 5, 3}
+
+	var tests = []struct {
+		name string
+		f    func([]float64) float64
+		want float64
+	}{
+		{"mean", Mean, 3.9},
+		{"max", Max, 9},
+		{"min", Min, 1},
+		{"p50", Quantile(0.5), 3},
+		{"p90", Quantile(0.9), 6},
+		{"p99", Quantile(0.99), 9},
+		{"p0", Quantile(0), 1},
+	}
+
+	for _, tt := range tests {
+		w := append([]float64(nil), xs...)
+		if got := tt.f(w); got != tt.want {
+			t.Errorf("%s(%v)=%f, wanted %f", tt.name, xs, got, tt.want)
+		}
+	}
+}
+
+func TestStreamAggregate(t *testing.T) {
+
+	s := NewStream(40, 5, 5, 0.95)
+	s.Aggregate = 10
+	s.AggregateFunc = Max
+
+	// the spikes only show up in the maximum of each group of 10 items
+	var r *ChangePoint
+	for i := 0; i < 1000 && r == nil; i++ {
+		v := 1.0
+		if i%10 == 0 {
+			v = 5
+			if i >= 600 {
+				v = 10
+			}
+		}
+		r = s.Push(v)
+	}
+
+	if r == nil || r.Offset != 60 {
+		t.Errorf("Stream aggregate returned %v, wanted change at 60", r)
+	}
+}
@@ -0,0 +1,111 @@
+package change
+
+// CheckAll returns every change point in the window, in order of index.  The
+// strongest change is found as by Check, and each side of it is searched
+// again until no more are found, so a window holding a dip and a recovery
+// reports both.  The Before and After statistics of each change point
+// describe the items between it and its neighbours.
+func (d *Detector) CheckAll(window []float64) []ChangePoint {
+	changes, _, _ := d.checkAll(window, nil)
+	return changes
+}
+
+// checkAll is CheckAll, using CheckWeighted if weights is not nil.  It also
+// returns the score and confidence of the best split of the whole window.
+func (d *Detector) checkAll(window, weights []float64) ([]ChangePoint, float64, float64) {
+	check := func(start, end int) (*ChangePoint, float64, float64) {
+		if weights != nil {
+			return d.checkWeighted(window[start:end], weights[start:end])
+		}
+		return d.check(window[start:end])
+	}
+
+	cp, score, conf := check(0, len(window))
+	if cp == nil {
+		return nil, score, conf
+	}
+
+	var changes []ChangePoint
+	d.searchAll(window, 0, len(window), cp, check, &changes)
+
+	// the statistics found while searching describe everything either
+	// side of each change, up to the edges of the part being searched
+	for i := range changes {
+		start, end := 0, len(window)
+		if i > 0 {
+			start = changes[i-1].Index
+		}
+		if i < len(changes)-1 {
+			end = changes[i+1].Index
+		}
+
+		cp := &changes[i]
+		if weights != nil {
+			cp.Before = newWeightedStats(window[start:cp.Index], weights[start:cp.Index])
+			cp.After = newWeightedStats(window[cp.Index:end], weights[cp.Index:end])
+		} else {
+			cp.Before, cp.After = newStats(window[start:cp.Index]), newStats(window[cp.Index:end])
+		}
+		cp.Difference = cp.After.mean - cp.Before.mean
+	}
+
+	return changes, score, conf
+}
+
+// searchAll records cp, the change found in window[start:end], and searches
+// either side of it
+func (d *Detector) searchAll(window []float64, start, end int, cp *ChangePoint, check func(start, end int) (*ChangePoint, float64, float64), changes *[]ChangePoint) {
+	idx := start + cp.Index
+
+	if cp, _, _ := check(start, idx); cp != nil {
+		d.searchAll(window, start, idx, cp, check, changes)
+	}
+
+	cp.Index, cp.Offset, cp.Lag = idx, idx, len(window)-idx
+	cp.IndexLow += start
+	cp.IndexHigh += start
+	*changes = append(*changes, *cp)
+
+	if cp, _, _ := check(idx, end); cp != nil {
+		d.searchAll(window, idx, end, cp, check, changes)
+	}
+}
+
+// PushAll is like Push, but reports every change point in the window found by
+// CheckAll, in order of offset.  Each change point passes through the same
+// options as those from Push.  With Confirm, only the most recent change
+// point is tentative and awaits confirmation, since the others are each
+// followed by a later change.
+func (s *Stream) PushAll(item float64) []ChangePoint {
+	return s.PushAllWeighted(item, 1)
+}
+
+// PushAllWeighted is PushAll for an item with the given weight, as for PushWeighted
+func (s *Stream) PushAllWeighted(item, weight float64) []ChangePoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ready(item, weight) {
+		return nil
+	}
+
+	if s.pending != nil {
+		if cp := s.confirmPending(); cp != nil {
+			return []ChangePoint{*cp}
+		}
+		return nil
+	}
+
+	var changes []ChangePoint
+	changes, s.lastScore, s.lastConfidence = s.detector.checkAll(s.data, s.weights)
+	s.checks++
+
+	var r []ChangePoint
+	for i := range changes {
+		if cp := s.found(&changes[i], i == len(changes)-1); cp != nil {
+			r = append(r, *cp)
+		}
+	}
+
+	return r
+}
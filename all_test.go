@@ -0,0 +1,144 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestCheckAll(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	// a dip between 40 and 80 that recovers
+	window := make([]float64, 120)
+	for i := range window {
+		window[i] = 10 + rnd.Float64()
+		if i >= 40 && i < 80 {
+			window[i] -= 5
+		}
+	}
+
+	d := Detector{MinSampleSize: 10, MinConfidence: 0.999}
+
+	changes := d.CheckAll(window)
+	if len(changes) != 2 || changes[0].Index != 40 || changes[1].Index != 80 {
+		t.Fatalf("CheckAll=%v, wanted changes at 40 and 80", changes)
+	}
+
+	if changes[0].Difference > -4 || changes[1].Difference < 4 {
+		t.Errorf("CheckAll differences=%f, %f, wanted about -5 and 5", changes[0].Difference, changes[1].Difference)
+	}
+
+	s := NewStream(120, 10, 10, 0.999)
+	var found []ChangePoint
+	for i := 0; i < 300 && len(found) < 2; i++ {
+		v := 10 + rnd.Float64()
+		if i >= 100 && i < 140 {
+			v -= 5
+		}
+		found = s.PushAll(v)
+	}
+
+	if len(found) != 2 || found[0].Offset != 100 {
+		t.Errorf("PushAll=%v, wanted changes at 100 and 140", found)
+	}
+}
+
+func TestPushAllOptions(t *testing.T) {
+
+	// a dip between 100 and 140 that recovers, one item a second
+	push := func(s *Stream, f func(s *Stream, v float64) []ChangePoint) []ChangePoint {
+		rnd := rand.New(rand.NewSource(1))
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		s.now = func() time.Time { return now }
+
+		var all []ChangePoint
+		for i := 0; i < 300; i++ {
+			now = now.Add(time.Second)
+			v := 10 + rnd.Float64()
+			if i >= 100 && i < 140 {
+				v -= 5
+			}
+			all = append(all, f(s, v)...)
+		}
+		return all
+	}
+	pushAll := func(s *Stream, v float64) []ChangePoint { return s.PushAll(v) }
+
+	var tests = []struct {
+		name  string
+		setup func(s *Stream)
+		push  func(s *Stream, v float64) []ChangePoint
+		check func(s *Stream, found []ChangePoint) bool
+	}{
+		{
+			"shadow",
+			func(s *Stream) { s.Shadow = true },
+			pushAll,
+			func(s *Stream, found []ChangePoint) bool {
+				for _, cp := range found {
+					if !cp.Shadow {
+						return false
+					}
+				}
+				return len(found) > 0
+			},
+		},
+		{
+			"suppressor",
+			func(s *Stream) {
+				s.Suppressor = SuppressorFunc(func(time.Time, *ChangePoint) bool { return true })
+			},
+			pushAll,
+			func(s *Stream, found []ChangePoint) bool { return len(found) == 0 && len(s.Recent(10)) == 0 },
+		},
+		{
+			"min interval",
+			func(s *Stream) { s.MinInterval = time.Hour },
+			pushAll,
+			func(s *Stream, found []ChangePoint) bool { return len(found) == 1 },
+		},
+		{
+			"history",
+			func(s *Stream) { s.History = 3 },
+			pushAll,
+			func(s *Stream, found []ChangePoint) bool {
+				recent := s.Recent(10)
+				return len(found) > 3 && len(recent) == 3 && recent[2].Offset == found[len(found)-1].Offset &&
+					s.Snapshot().LastChange != nil
+			},
+		},
+		{
+			"weights",
+			nil,
+			func(s *Stream, v float64) []ChangePoint { return s.PushAllWeighted(v, 2) },
+			func(s *Stream, found []ChangePoint) bool {
+				return len(found) > 0 && s.weights != nil && found[0].Offset == 100
+			},
+		},
+		{
+			"confirm",
+			func(s *Stream) { s.Confirm = 20 },
+			pushAll,
+			func(s *Stream, found []ChangePoint) bool {
+				var tentative, confirmed bool
+				for _, cp := range found {
+					tentative = tentative || cp.Tentative
+					confirmed = confirmed || (!cp.Tentative && cp.Offset == 140)
+				}
+				return tentative && confirmed
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s := NewStream(120, 10, 10, 0.999)
+		if tt.setup != nil {
+			tt.setup(s)
+		}
+		if found := push(s, tt.push); !tt.check(s, found) {
+			t.Errorf("%s: PushAll=%v", tt.name, found)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package annotation records detected change points for later review
+/*
+Detected change points are stored as annotations along with the series they
+were found in, and can be acknowledged and queried later.  Storage is
+pluggable: MemStore keeps annotations in memory, FileStore appends them to a
+JSON-lines file, and SQLStore uses any database/sql driver.
+*/
+package annotation
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+// ErrNotFound is returned when acknowledging an annotation that does not exist
+var ErrNotFound = errors.New("annotation: not found")
+
+// Annotation is a recorded change point
+type Annotation struct {
+	ID         int64     `json:"id"`
+	Series     string    `json:"series"`
+	Time       time.Time `json:"time"`
+	Offset     int       `json:"offset"`
+	Difference float64   `json:"difference"`
+	Confidence float64   `json:"confidence"`
+	Score      float64   `json:"score"`
+	AckedBy    string    `json:"acked_by,omitempty"`
+	AckedAt    time.Time `json:"acked_at,omitzero"`
+}
+
+// New returns an annotation for a change point found in series at time t
+func New(series string, t time.Time, cp *change.ChangePoint) Annotation {
+	return Annotation{
+		Series:     series,
+		Time:       t,
+		Offset:     cp.Offset,
+		Difference: cp.Difference,
+		Confidence: cp.Confidence,
+		Score:      cp.Score,
+	}
+}
+
+// Acked reports whether the annotation has been acknowledged
+func (a *Annotation) Acked() bool { return a.AckedBy != "" }
+
+// Query selects annotations.  Zero-valued fields match everything.
+type Query struct {
+	// Series matches annotations for a single series
+	Series string
+
+	// From and To match annotations with From <= Time < To
+	From, To time.Time
+
+	// Unacked matches only annotations which have not been acknowledged
+	Unacked bool
+}
+
+// Match reports whether a is selected by the query
+func (q *Query) Match(a *Annotation) bool {
+	if q.Series != "" && a.Series != q.Series {
+		return false
+	}
+	if !q.From.IsZero() && a.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !a.Time.Before(q.To) {
+		return false
+	}
+	if q.Unacked && a.Acked() {
+		return false
+	}
+	return true
+}
+
+// Store is a durable collection of annotations
+type Store interface {
+	// Add records a new annotation and returns its ID
+	Add(a Annotation) (int64, error)
+
+	// Ack marks the annotation with the given ID as acknowledged
+	Ack(id int64, by string, at time.Time) error
+
+	// Query returns the matching annotations ordered by time
+	Query(q Query) ([]Annotation, error)
+}
+
+// MemStore is an in-memory Store.  It is safe for concurrent use.
+type MemStore struct {
+	mu     sync.Mutex
+	nextID int64
+	items  []Annotation
+}
+
+// NewMemStore returns an empty in-memory store
+func NewMemStore() *MemStore {
+	return &MemStore{nextID: 1}
+}
+
+// Add implements Store
+func (m *MemStore) Add(a Annotation) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a.ID = m.nextID
+	m.nextID++
+	m.items = append(m.items, a)
+
+	return a.ID, nil
+}
+
+// Ack implements Store
+func (m *MemStore) Ack(id int64, by string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a := m.find(id)
+	if a == nil {
+		return ErrNotFound
+	}
+
+	a.AckedBy, a.AckedAt = by, at
+
+	return nil
+}
+
+// Query implements Store
+func (m *MemStore) Query(q Query) ([]Annotation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var r []Annotation
+	for i := range m.items {
+		if q.Match(&m.items[i]) {
+			r = append(r, m.items[i])
+		}
+	}
+
+	sort.SliceStable(r, func(i, j int) bool { return r[i].Time.Before(r[j].Time) })
+
+	return r, nil
+}
+
+// find returns the annotation with the given ID.  The lock must be held.
+func (m *MemStore) find(id int64) *Annotation {
+	for i := range m.items {
+		if m.items[i].ID == id {
+			return &m.items[i]
+		}
+	}
+	return nil
+}
+
+// put inserts or replaces an annotation, keeping its ID.  The lock must be held.
+func (m *MemStore) put(a Annotation) {
+	if p := m.find(a.ID); p != nil {
+		*p = a
+	} else {
+		m.items = append(m.items, a)
+	}
+	if a.ID >= m.nextID {
+		m.nextID = a.ID + 1
+	}
+}
@@ -0,0 +1,92 @@
+package annotation
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T, s Store) {
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, series := range []string{"cpu", "mem", "cpu"} {
+		a := Annotation{Series: series, Time: start.Add(time.Duration(i) * time.Minute), Confidence: 0.99}
+		if _, err := s.Add(a); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	cpu, err := s.Query(Query{Series: "cpu"})
+	if err != nil || len(cpu) != 2 {
+		t.Fatalf("Query(cpu)=%v, %v, wanted 2 annotations", cpu, err)
+	}
+
+	if err := s.Ack(cpu[0].ID, "oncall", start.Add(time.Hour)); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+
+	if err := s.Ack(1000, "oncall", start); err != ErrNotFound {
+		t.Errorf("Ack(unknown)=%v, wanted ErrNotFound", err)
+	}
+
+	unacked, _ := s.Query(Query{Unacked: true})
+	if len(unacked) != 2 {
+		t.Errorf("Query(unacked) returned %d annotations, wanted 2", len(unacked))
+	}
+
+	ranged, _ := s.Query(Query{From: start.Add(time.Minute), To: start.Add(2 * time.Minute)})
+	if len(ranged) != 1 || ranged[0].Series != "mem" {
+		t.Errorf("Query(range)=%v, wanted the mem annotation", ranged)
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	testStore(t, NewMemStore())
+}
+
+func TestFileStore(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "annotations.jsonl")
+
+	s, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	testStore(t, s)
+	s.Close()
+
+	// reopening the file should restore the annotations and acknowledgements
+	s, err = OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	defer s.Close()
+
+	all, _ := s.Query(Query{})
+	if len(all) != 3 || !all[0].Acked() || all[0].AckedBy != "oncall" {
+		t.Errorf("reopened store contains %v", all)
+	}
+
+	id, _ := s.Add(Annotation{Series: "disk"})
+	if id != 4 {
+		t.Errorf("Add after reopen returned id %d, wanted 4", id)
+	}
+}
+
+func TestAnnotationJSON(t *testing.T) {
+
+	a := Annotation{ID: 1, Series: "cpu"}
+	buf, err := json.Marshal(a)
+	if err != nil || strings.Contains(string(buf), "acked") {
+		t.Errorf("Marshal of an unacknowledged annotation=%s, %v, wanted no acked fields", buf, err)
+	}
+
+	a.AckedBy, a.AckedAt = "alice", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buf, err = json.Marshal(a)
+	if err != nil || !strings.Contains(string(buf), `"acked_at":"2024-01-01T00:00:00Z"`) {
+		t.Errorf("Marshal of an acknowledged annotation=%s, %v, wanted acked_at", buf, err)
+	}
+}
@@ -0,0 +1,95 @@
+package annotation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// FileStore is a Store backed by a JSON-lines file.  Every change to an
+// annotation appends its full state to the file, and the most recent line for
+// each ID wins when the file is loaded.  It is safe for concurrent use.
+type FileStore struct {
+	mem *MemStore
+	f   *os.File
+}
+
+// OpenFile opens or creates the file at path and loads any annotations already in it
+func OpenFile(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	mem := NewMemStore()
+
+	scanner := bufio.NewScanner(f)
+	var line int
+	for scanner.Scan() {
+		line++
+		var a Annotation
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("annotation: %s:%d: %w", path, line, err)
+		}
+		mem.put(a)
+	}
+
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &FileStore{mem: mem, f: f}, nil
+}
+
+// Close closes the underlying file
+func (s *FileStore) Close() error { return s.f.Close() }
+
+// Add implements Store
+func (s *FileStore) Add(a Annotation) (int64, error) {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	a.ID = s.mem.nextID
+	if err := s.write(&a); err != nil {
+		return 0, err
+	}
+	s.mem.put(a)
+
+	return a.ID, nil
+}
+
+// Ack implements Store
+func (s *FileStore) Ack(id int64, by string, at time.Time) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	p := s.mem.find(id)
+	if p == nil {
+		return ErrNotFound
+	}
+
+	a := *p
+	a.AckedBy, a.AckedAt = by, at
+	if err := s.write(&a); err != nil {
+		return err
+	}
+	*p = a
+
+	return nil
+}
+
+// Query implements Store
+func (s *FileStore) Query(q Query) ([]Annotation, error) { return s.mem.Query(q) }
+
+func (s *FileStore) write(a *Annotation) error {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	_, err = s.f.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,140 @@
+package annotation
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLStore is a Store backed by a database/sql table with the columns
+//
+//	id            integer primary key, assigned by the database
+//	series        text
+//	time          timestamp
+//	change_offset integer
+//	difference    double
+//	confidence    double
+//	score         double
+//	acked_by      text
+//	acked_at      timestamp, nullable
+//
+// The table must already exist.
+type SQLStore struct {
+	DB    *sql.DB
+	Table string
+
+	// Placeholder returns the bind parameter for the n'th (1-based)
+	// argument of a statement.  If nil, "?" is used; set it to return
+	// "$1", "$2", ... for PostgreSQL.
+	Placeholder func(n int) string
+
+	// Returning makes Add read the ID of the new row with INSERT ...
+	// RETURNING id instead of LastInsertId, which PostgreSQL drivers do
+	// not support
+	Returning bool
+}
+
+func (s *SQLStore) ph(n int) string {
+	if s.Placeholder == nil {
+		return "?"
+	}
+	return s.Placeholder(n)
+}
+
+func (s *SQLStore) phs(from, count int) string {
+	p := make([]string, count)
+	for i := range p {
+		p[i] = s.ph(from + i)
+	}
+	return strings.Join(p, ", ")
+}
+
+// Add implements Store
+func (s *SQLStore) Add(a Annotation) (int64, error) {
+	q := fmt.Sprintf(`INSERT INTO %s (series, time, change_offset, difference, confidence, score, acked_by) VALUES (%s)`, s.Table, s.phs(1, 7))
+	args := []interface{}{a.Series, a.Time, a.Offset, a.Difference, a.Confidence, a.Score, a.AckedBy}
+
+	if s.Returning {
+		var id int64
+		err := s.DB.QueryRow(q+" RETURNING id", args...).Scan(&id)
+		return id, err
+	}
+
+	r, err := s.DB.Exec(q, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.LastInsertId()
+}
+
+// Ack implements Store
+func (s *SQLStore) Ack(id int64, by string, at time.Time) error {
+	q := fmt.Sprintf(`UPDATE %s SET acked_by = %s, acked_at = %s WHERE id = %s`, s.Table, s.ph(1), s.ph(2), s.ph(3))
+
+	r, err := s.DB.Exec(q, by, at, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Query implements Store
+func (s *SQLStore) Query(query Query) ([]Annotation, error) {
+	var where []string
+	var args []interface{}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, s.ph(len(args))))
+	}
+
+	if query.Series != "" {
+		add("series = %s", query.Series)
+	}
+	if !query.From.IsZero() {
+		add("time >= %s", query.From)
+	}
+	if !query.To.IsZero() {
+		add("time < %s", query.To)
+	}
+	if query.Unacked {
+		where = append(where, "(acked_by IS NULL OR acked_by = '')")
+	}
+
+	q := fmt.Sprintf(`SELECT id, series, time, change_offset, difference, confidence, score, acked_by, acked_at FROM %s`, s.Table)
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY time"
+
+	rows, err := s.DB.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var r []Annotation
+	for rows.Next() {
+		var a Annotation
+		var ackedBy sql.NullString
+		var ackedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.Series, &a.Time, &a.Offset, &a.Difference, &a.Confidence, &a.Score, &ackedBy, &ackedAt); err != nil {
+			return nil, err
+		}
+		a.AckedBy, a.AckedAt = ackedBy.String, ackedAt.Time
+		r = append(r, a)
+	}
+
+	return r, rows.Err()
+}
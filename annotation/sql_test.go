@@ -0,0 +1,246 @@
+package annotation
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSQLStore(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		postgres bool
+		store    func(db *sql.DB) *SQLStore
+	}{
+		{"sqlite", false, func(db *sql.DB) *SQLStore { return &SQLStore{DB: db, Table: "annotations"} }},
+		{"postgres", true, func(db *sql.DB) *SQLStore {
+			return &SQLStore{
+				DB:          db,
+				Table:       "annotations",
+				Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+				Returning:   true,
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		db := sql.OpenDB(&fakeConnector{db: &fakeDB{postgres: tt.postgres}})
+		s := tt.store(db)
+
+		testStore(t, s)
+
+		id, err := s.Add(Annotation{Series: "disk", Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Score: 42})
+		if err != nil || id != 4 {
+			t.Fatalf("%s: Add=%d, %v, wanted id 4", tt.name, id, err)
+		}
+
+		disk, err := s.Query(Query{Series: "disk"})
+		if err != nil || len(disk) != 1 || disk[0].ID != 4 || disk[0].Score != 42 {
+			t.Errorf("%s: Query(disk)=%v, %v, wanted id 4 with score 42", tt.name, disk, err)
+		}
+
+		db.Close()
+	}
+
+	// without Returning, Add fails on drivers without LastInsertId
+	db := sql.OpenDB(&fakeConnector{db: &fakeDB{postgres: true}})
+	defer db.Close()
+
+	s := &SQLStore{DB: db, Table: "annotations", Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) }}
+	if _, err := s.Add(Annotation{Series: "cpu"}); err == nil {
+		t.Errorf("Add without Returning succeeded on a driver without LastInsertId")
+	}
+}
+
+// fakeDB is a table of annotations behind a fake database/sql driver, which
+// understands only the statements used by SQLStore.  In postgres mode it
+// requires $n placeholders and does not support LastInsertId, like the
+// PostgreSQL drivers.
+type fakeDB struct {
+	postgres bool
+	rows     []fakeRow
+}
+
+type fakeRow struct {
+	id                            int64
+	series                        string
+	time                          time.Time
+	offset                        int64
+	difference, confidence, score float64
+	ackedBy                       string
+	ackedAt                       time.Time
+}
+
+type fakeConnector struct{ db *fakeDB }
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{c.db}, nil }
+func (c *fakeConnector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return nil, errors.New("fake: use OpenDB") }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if c.db.postgres && strings.Contains(query, "?") || !c.db.postgres && strings.Contains(query, "$") {
+		return nil, fmt.Errorf("fake: syntax error in %q", query)
+	}
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("fake: no transactions") }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	switch {
+	case strings.HasPrefix(s.query, "INSERT"):
+		id := s.insert(args)
+		return fakeResult{id: id, postgres: s.db.postgres}, nil
+
+	case strings.HasPrefix(s.query, "UPDATE"):
+		var n int64
+		for i := range s.db.rows {
+			if r := &s.db.rows[i]; r.id == args[2].(int64) {
+				r.ackedBy, r.ackedAt = args[0].(string), args[1].(time.Time)
+				n++
+			}
+		}
+		return fakeResult{rows: n, postgres: s.db.postgres}, nil
+	}
+
+	return nil, fmt.Errorf("fake: unsupported statement %q", s.query)
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	switch {
+	case strings.HasPrefix(s.query, "INSERT") && strings.HasSuffix(s.query, " RETURNING id"):
+		if !s.db.postgres {
+			return nil, fmt.Errorf("fake: syntax error in %q", s.query)
+		}
+		id := s.insert(args)
+		return &fakeRows{columns: []string{"id"}, values: [][]driver.Value{{id}}}, nil
+
+	case strings.HasPrefix(s.query, "SELECT"):
+		return s.selectRows(args), nil
+	}
+
+	return nil, fmt.Errorf("fake: unsupported query %q", s.query)
+}
+
+func (s *fakeStmt) insert(args []driver.Value) int64 {
+	id := int64(len(s.db.rows) + 1)
+	s.db.rows = append(s.db.rows, fakeRow{
+		id:         id,
+		series:     args[0].(string),
+		time:       args[1].(time.Time),
+		offset:     args[2].(int64),
+		difference: args[3].(float64),
+		confidence: args[4].(float64),
+		score:      args[5].(float64),
+		ackedBy:    args[6].(string),
+	})
+	return id
+}
+
+func (s *fakeStmt) selectRows(args []driver.Value) driver.Rows {
+	var conds []string
+	if _, where, ok := strings.Cut(s.query, " WHERE "); ok {
+		where, _, _ = strings.Cut(where, " ORDER BY")
+		conds = strings.Split(where, " AND ")
+	}
+
+	match := func(r *fakeRow) bool {
+		next := 0
+		for _, c := range conds {
+			var arg driver.Value
+			if !strings.HasPrefix(c, "(") {
+				arg, next = args[next], next+1
+			}
+			switch {
+			case strings.HasPrefix(c, "series = "):
+				if r.series != arg.(string) {
+					return false
+				}
+			case strings.HasPrefix(c, "time >= "):
+				if r.time.Before(arg.(time.Time)) {
+					return false
+				}
+			case strings.HasPrefix(c, "time < "):
+				if !r.time.Before(arg.(time.Time)) {
+					return false
+				}
+			case strings.HasPrefix(c, "(acked_by IS NULL"):
+				if r.ackedBy != "" {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	var matched []fakeRow
+	for i := range s.db.rows {
+		if match(&s.db.rows[i]) {
+			matched = append(matched, s.db.rows[i])
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].time.Before(matched[j].time) })
+
+	rows := &fakeRows{columns: []string{"id", "series", "time", "change_offset", "difference", "confidence", "score", "acked_by", "acked_at"}}
+	for _, r := range matched {
+		var ackedAt driver.Value
+		if !r.ackedAt.IsZero() {
+			ackedAt = r.ackedAt
+		}
+		rows.values = append(rows.values, []driver.Value{r.id, r.series, r.time, r.offset, r.difference, r.confidence, r.score, r.ackedBy, ackedAt})
+	}
+	return rows
+}
+
+type fakeResult struct {
+	id, rows int64
+	postgres bool
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	if r.postgres {
+		return 0, errors.New("fake: LastInsertId is not supported by this driver")
+	}
+	return r.id, nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) { return r.rows, nil }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,42 @@
+package change
+
+// autocorrelation returns the lag-1 autocorrelation of the residuals of window
+// from mean1 before index idx and mean2 after it.  The pair of items either
+// side of the change point is not included.
+func autocorrelation(window []float64, idx int, mean1, mean2 float64) float64 {
+	resid := func(i int) float64 {
+		if i < idx {
+			return window[i] - mean1
+		}
+		return window[i] - mean2
+	}
+
+	var num, den float64
+	for i := range window {
+		r := resid(i)
+		den += r * r
+		if i > 0 && i != idx {
+			num += r * resid(i-1)
+		}
+	}
+
+	if den == 0 {
+		return 0
+	}
+
+	return num / den
+}
+
+// effectiveLen returns the effective number of independent samples in n
+// items with lag-1 autocorrelation rho, assuming an AR(1) process.
+func effectiveLen(n int, rho float64) int {
+	if rho <= 0 {
+		return n
+	}
+
+	ne := int(float64(n) * (1 - rho) / (1 + rho))
+	if ne < 2 {
+		ne = 2
+	}
+	return ne
+}
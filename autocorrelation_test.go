@@ -0,0 +1,52 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAdjustAutocorrelation(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	plain := Detector{MinSampleSize: 20, MinConfidence: 0.99}
+	adjusted := Detector{MinSampleSize: 20, MinConfidence: 0.99, AdjustAutocorrelation: true}
+
+	// smooth AR(1) series with no change point
+	var plainFalse, adjustedFalse int
+	for i := 0; i < 50; i++ {
+		w := make([]float64, 200)
+		for j := 1; j < len(w); j++ {
+			w[j] = 0.9*w[j-1] + rnd.NormFloat64()
+		}
+
+		if plain.Check(w) != nil {
+			plainFalse++
+		}
+
+		if cp := adjusted.Check(w); cp != nil {
+			adjustedFalse++
+			if math.Abs(cp.Autocorrelation-0.9) > 0.2 {
+				t.Errorf("estimated autocorrelation=%f, wanted about 0.9", cp.Autocorrelation)
+			}
+		}
+	}
+
+	if adjustedFalse*5 > plainFalse {
+		t.Errorf("false positives with adjustment=%d, without=%d", adjustedFalse, plainFalse)
+	}
+
+	// a real step is still found
+	w := make([]float64, 200)
+	for j := range w {
+		w[j] = rnd.NormFloat64()
+		if j >= 100 {
+			w[j] += 3
+		}
+	}
+
+	if cp := adjusted.Check(w); cp == nil || cp.Index != 100 {
+		t.Errorf("adjusted Check on step=%v, wanted change at 100", cp)
+	}
+}
@@ -0,0 +1,65 @@
+// Package bootstrap computes significance levels for change points by resampling
+/*
+The t-test confidence reported by change.Detector assumes independent
+samples and ignores that the change point was chosen as the best of many
+candidate positions.  On autocorrelated data both assumptions make it
+overconfident.  PValue instead estimates the distribution of the detector's
+score on data with no change point by a moving block bootstrap, which keeps
+the autocorrelation within each block.
+*/
+package bootstrap
+
+import (
+	"math/rand"
+
+	"github.com/dgryski/go-change"
+)
+
+// Resample fills dst with a moving block bootstrap resample of series: blocks
+// of blockLen consecutive items starting at random positions, concatenated
+// until dst is full.  Because the blocks are drawn from the whole series, any
+// change point in it is destroyed while short-range autocorrelation is kept.
+func Resample(dst, series []float64, blockLen int, rnd *rand.Rand) {
+	n := len(series)
+	if blockLen > n {
+		blockLen = n
+	}
+	if blockLen < 1 {
+		blockLen = 1
+	}
+
+	for i := 0; i < len(dst); {
+		start := rnd.Intn(n - blockLen + 1)
+		i += copy(dst[i:], series[start:start+blockLen])
+	}
+}
+
+// PValue returns the fraction of iterations block bootstrap resamples of
+// series for which d finds a change point with at least the Score of the one
+// it finds in series itself.  The score is used rather than the confidence
+// because the confidence saturates at 1 for large changes.  The detector's
+// MinConfidence is ignored.  If no change point is found in series the
+// p-value is 1.
+func PValue(d *change.Detector, series []float64, blockLen int, iterations int, rnd *rand.Rand) float64 {
+
+	detector := *d
+	detector.MinConfidence = 0
+
+	observed := detector.Check(series)
+	if observed == nil {
+		return 1
+	}
+
+	sample := make([]float64, len(series))
+
+	var exceed int
+	for i := 0; i < iterations; i++ {
+		Resample(sample, series, blockLen, rnd)
+		if cp := detector.Check(sample); cp != nil && cp.Score >= observed.Score {
+			exceed++
+		}
+	}
+
+	// count the observed series itself so the estimate is never zero
+	return float64(exceed+1) / float64(iterations+1)
+}
@@ -0,0 +1,70 @@
+package bootstrap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+// ar1 returns a strongly autocorrelated series with no change point
+func ar1(rnd *rand.Rand, n int, phi float64) []float64 {
+	xs := make([]float64, n)
+	for i := 1; i < n; i++ {
+		xs[i] = phi*xs[i-1] + rnd.NormFloat64()
+	}
+	return xs
+}
+
+func TestPValue(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	d := &change.Detector{MinSampleSize: 20}
+
+	step := make([]float64, 200)
+	for i := range step {
+		step[i] = rnd.NormFloat64()
+		if i >= 100 {
+			step[i] += 3
+		}
+	}
+
+	if p := PValue(d, step, 10, 200, rnd); p > 0.01 {
+		t.Errorf("PValue on step=%f, wanted significant", p)
+	}
+
+	// the t-test is overconfident on autocorrelated data, the bootstrap is not
+	var tFalse, bFalse int
+	for i := 0; i < 20; i++ {
+		series := ar1(rnd, 200, 0.9)
+		if cp := d.Check(series); cp != nil && cp.Confidence > 0.99 {
+			tFalse++
+		}
+		if PValue(d, series, 20, 100, rnd) < 0.01 {
+			bFalse++
+		}
+	}
+
+	if bFalse >= tFalse {
+		t.Errorf("bootstrap false positives=%d, t-test=%d, wanted fewer from the bootstrap", bFalse, tFalse)
+	}
+}
+
+func TestResample(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	series := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	dst := make([]float64, 25)
+	Resample(dst, series, 4, rnd)
+
+	// each block is a run of consecutive values
+	for i := 0; i < len(dst); i += 4 {
+		for j := i + 1; j < i+4 && j < len(dst); j++ {
+			if dst[j] != dst[j-1]+1 {
+				t.Fatalf("Resample=%v, block at %d is not consecutive", dst, i)
+			}
+		}
+	}
+}
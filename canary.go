@@ -0,0 +1,35 @@
+package change
+
+// CanaryResult is the outcome of comparing a canary series with its baseline
+type CanaryResult struct {
+	// Change is the change found in the canary minus the baseline, or nil
+	// if the canary still tracks the baseline.  Its Difference is the size
+	// of the canary's change relative to the baseline.
+	Change *ChangePoint
+
+	// Baseline and Canary are the changes found in each series on its
+	// own, if any
+	Baseline *ChangePoint
+	Canary   *ChangePoint
+}
+
+// CheckCanary reports whether the canary series has a change point that the
+// baseline does not.  The series are aligned at their ends, so the last items
+// of each are taken to be simultaneous and any extra items at the start of
+// the longer series are ignored.  Changes common to both series, such as a
+// shift in traffic, cancel out in the difference between them.
+func (d *Detector) CheckCanary(baseline, canary []float64) CanaryResult {
+	n := min(len(baseline), len(canary))
+	baseline, canary = baseline[len(baseline)-n:], canary[len(canary)-n:]
+
+	diff := make([]float64, n)
+	for i := range diff {
+		diff[i] = canary[i] - baseline[i]
+	}
+
+	return CanaryResult{
+		Change:   d.Check(diff),
+		Baseline: d.Check(baseline),
+		Canary:   d.Check(canary),
+	}
+}
@@ -0,0 +1,55 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCheckCanary(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	var tests = []struct {
+		name          string
+		baselineShift float64
+		canaryShift   float64
+		regressed     bool
+	}{
+		{"no change", 0, 0, false},
+		{"both change", 5, 5, false},
+		{"canary regresses", 0, 5, true},
+		{"canary regresses more", 5, 10, true},
+	}
+
+	d := &Detector{MinSampleSize: 20, MinConfidence: 0.999}
+
+	for _, tt := range tests {
+		var baseline, canary []float64
+		for i := 0; i < 200; i++ {
+			b, c := rnd.Float64(), rnd.Float64()
+			if i >= 100 {
+				b += tt.baselineShift
+				c += tt.canaryShift
+			}
+			baseline = append(baseline, b)
+			canary = append(canary, c)
+		}
+
+		// extra history at the start of the baseline is ignored
+		baseline = append([]float64{100, 100, 100}, baseline...)
+
+		r := d.CheckCanary(baseline, canary)
+		if got := r.Change != nil; got != tt.regressed {
+			t.Errorf("CheckCanary(%s) change=%v, wanted regressed=%v", tt.name, r.Change, tt.regressed)
+			continue
+		}
+
+		if r.Change != nil && (r.Change.Index != 100 || r.Change.Difference < 4 || r.Change.Difference > 6) {
+			t.Errorf("CheckCanary(%s) found change at %d of %f, wanted 5 at 100", tt.name, r.Change.Index, r.Change.Difference)
+		}
+
+		if (r.Canary != nil) != (tt.canaryShift != 0) {
+			t.Errorf("CheckCanary(%s) canary change=%v", tt.name, r.Canary)
+		}
+	}
+}
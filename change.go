@@ -18,9 +18,13 @@ the two distributions to reduce the rate of false positives.
 package change
 
 import (
+	"fmt"
+	"log/slog"
 	"math"
+	"sync"
+	"time"
 
-	"github.com/dgryski/go-onlinestats"
+	"github.com/dgryski/go-change/trend"
 )
 
 // Stats are some descriptive statistics for a block of items.  It implements the interface needed by the t-test method of onlinestats.
@@ -28,6 +32,9 @@ type Stats struct {
 	mean     float64
 	variance float64
 	n        int
+
+	// roundoff bounds the rounding error in variance
+	roundoff float64
 }
 
 // Mean returns the mean of the data set
@@ -42,55 +49,202 @@ func (s Stats) Len() int { return s.n }
 // Stddev returns the standard deviation of the sample
 func (s Stats) Stddev() float64 { return math.Sqrt(s.variance) }
 
+// newStats computes the statistics of xs
+func newStats(xs []float64) Stats {
+	var shift float64
+	if len(xs) > 0 {
+		shift = xs[0]
+	}
+
+	var sum, sumsq float64
+	for _, v := range xs {
+		v -= shift
+		sum += v
+		sumsq += v * v
+	}
+
+	n := float64(len(xs))
+	return Stats{
+		mean:     sum/n + shift,
+		variance: (sumsq - sum*sum/n) / (n - 1),
+		n:        len(xs),
+		roundoff: sumsRoundoff(len(xs), sumsq) / (n - 1),
+	}
+}
+
 // ChangePoint is a potential change point found by Check().
 type ChangePoint struct {
 	// Index is the offset into the data set of the suspected change point
 	Index int
 
+	// IndexLow and IndexHigh bound an approximate 95% confidence interval
+	// for Index, based on the profile likelihood of the change point
+	// location.  A narrow interval means the change is sharply located; a
+	// wide one that it happened somewhere in a larger region.  Subtract
+	// Index to get the interval relative to Offset.
+	IndexLow, IndexHigh int
+
 	// Difference is the difference in distribution means found by the Student's t-test
 	Difference float64
 
 	// Confidence is the confidence returned by a Student's t-test
 	Confidence float64
 
+	// Score is the test statistic that was maximized to locate the change
+	// point: the between-class scatter for Detector, and the likelihood
+	// ratio for RateDetector.
+	Score float64
+
 	// Before is the statistics of the distribution before the change point
 	Before Stats
 
 	// After is the statistics of the distribution after the change point
 	After Stats
+
+	// Autocorrelation is the lag-1 autocorrelation of the window around the
+	// means of each side.  It is only set if the detector's
+	// AdjustAutocorrelation option is enabled.
+	Autocorrelation float64
+
+	// BeforeTrend and AfterTrend describe the trend within the distributions
+	// before and after the change point.  They are only set if the
+	// detector's SegmentTrends option is enabled.
+	BeforeTrend, AfterTrend Trend
+
+	// Offset is the estimated index of the change point counted from the
+	// start of the data.  For a single Check this is the same as Index; for
+	// a Stream it is the number of items pushed before the change began.
+	Offset int
+
+	// Lag is the number of items seen since the change began, i.e. how far
+	// in the past the change is at the time it was detected.  Multiply by
+	// the sampling interval to get the detection latency as a duration.
+	Lag int
+
+	// Time is the timestamp of the first item after the change point.  It is
+	// only set for change points found by a TimeStream.
+	Time time.Time
+
+	// Detected is the time a Stream reported the change point
+	Detected time.Time
+
+	// Shadow is set for change points reported by a stream with the Shadow
+	// option, which should be recorded but not acted on
+	Shadow bool
+
+	// Suppressed is the number of change points a Stream with the
+	// MinInterval option dropped since the previous one it reported
+	Suppressed int
+
+	// ZeroVariance is set if neither side of the change point has any
+	// variance, such as for a step in a noiseless series.  The t-test is
+	// undefined, so Confidence is 1.
+	ZeroVariance bool
+
+	// Tentative is set for change points reported by a stream with the
+	// Confirm option that have not yet been confirmed.
+	Tentative bool
+
+	// Window is a copy of the data window the change point was found in.
+	// It is only set if the stream's CaptureWindow option is enabled.
+	Window []float64
+}
+
+// EffectSize returns the difference in means scaled by the pooled standard
+// deviation of the two distributions (Cohen's d).  Unlike Difference, it does
+// not depend on the units of the data, so one threshold can be used across
+// metrics with very different scales.  If neither side has any variance,
+// as for ZeroVariance, the effect size is 0 when the means are equal up to
+// rounding error, and +Inf or -Inf in the direction of the change otherwise.
+func (cp *ChangePoint) EffectSize() float64 {
+	if zeroVariance(cp.Before) && zeroVariance(cp.After) {
+		if welch(cp.Before, cp.After) == 0 {
+			return 0
+		}
+		return math.Copysign(math.Inf(1), cp.Difference)
+	}
+
+	n1, n2 := float64(cp.Before.n), float64(cp.After.n)
+	pooled := ((n1-1)*cp.Before.variance + (n2-1)*cp.After.variance) / (n1 + n2 - 2)
+	return cp.Difference / math.Sqrt(pooled)
+}
+
+// Trend is the result of a Mann-Kendall trend test on a segment of data
+type Trend struct {
+	// Tau is Kendall's tau; positive for an increasing trend and negative for a decreasing one
+	Tau float64
+
+	// P is the p-value of the test; small values indicate a significant trend
+	P float64
 }
 
+// chiSquared95 is the 95th percentile of the chi-squared distribution with one degree of freedom
+const chiSquared95 = 3.841458820694124
+
 // DefaultMinSampleSize is the minimum sample size to consider from the window being checked
 const DefaultMinSampleSize = 30
 
 // Detector is a change detector.
+//
+// Both the location of the change point and its confidence are unaffected by
+// shifting or scaling the window, so there is no need to normalize data
+// before checking it; the same MinConfidence works for any metric.
 type Detector struct {
+	// MinSampleSize is the smallest number of items allowed on either side
+	// of a change point.  Candidate positions closer than this to the ends
+	// of the window are never considered, so it also acts as the minimum
+	// segment length.  If zero or negative, DefaultMinSampleSize is used,
+	// and a value of 1 is raised to 2, the fewest items with a variance.
 	MinSampleSize int
+
+	// MinConfidence is the t-test confidence a change point must exceed to be reported
 	MinConfidence float64
+
+	// AdjustAutocorrelation reduces the sample sizes passed to the t-test
+	// to their effective sizes given the lag-1 autocorrelation of the
+	// window, so that smooth but unchanged series are not reported as
+	// changing.
+	AdjustAutocorrelation bool
+
+	// Logger, if set, receives a debug record of the best split of each
+	// window checked, whether or not it was reported
+	Logger *slog.Logger
+
+	// SegmentTrends causes a trend test to be run on each side of a change
+	// point found.  This is O(n^2) in the window size.
+	SegmentTrends bool
+}
+
+// minSampleSize returns the minimum sample size to use
+func (d *Detector) minSampleSize() int {
+	switch {
+	case d.MinSampleSize <= 0:
+		return DefaultMinSampleSize
+	case d.MinSampleSize == 1:
+		return 2
+	}
+	return d.MinSampleSize
 }
 
-// Check returns the index of a potential change point
+// Check returns the index of a potential change point.  Windows shorter
+// than twice the minimum sample size have no candidate positions, so no
+// change point is found in them.  See Validate for the other requirements
+// on the window.
 func (d *Detector) Check(window []float64) *ChangePoint {
+	cp, _, _ := d.check(window)
+	return cp
+}
+
+// check is Check, also returning the score and confidence of the best split
+// whether or not it is a change point
+func (d *Detector) check(window []float64) (*ChangePoint, float64, float64) {
 
 	n := len(window)
 
 	// The paper provides recursive formulas for computing the means and
 	// standard deviations as we slide along the window.  This
 	// implementation uses alternate math based on cumulative sums.
-
-	// cumsum contains the cumulative sum of all elements <= i
-	// cumsumsq contains the cumulative sum of squares of all elements <= i
-	// TODO(dgryski): move this to a move numerically stable algorithm
-	cumsum := make([]float64, n)
-	cumsumsq := make([]float64, n)
-
-	var sum, sumsq float64
-	for i, v := range window {
-		sum += v
-		sumsq += v * v
-		cumsum[i] = sum
-		cumsumsq[i] = sumsq
-	}
+	sp := newSplits(window)
 
 	// sb is our between-class scatter, the degree of dissimilarity of the
 	// two distributions.  This value is always positive, so we can set 0
@@ -106,62 +260,150 @@ func (d *Detector) Check(window []float64) *ChangePoint {
 	// variances.  However, we calculate the variances so that we can pass
 	// them to the T test later on.
 
-	var before, after Stats
-
-	// sane default
-	minSampleSize := d.MinSampleSize
-	if minSampleSize == 0 {
-		minSampleSize = DefaultMinSampleSize
-	}
+	minSampleSize := d.minSampleSize()
 
 	for l := minSampleSize; l < (n - minSampleSize + 1); l++ {
-		lidx := l - 1
-		n1 := float64(l)
-		mean1 := cumsum[lidx] / n1
-
-		n2 := float64(n - l)
-		sum2 := (sum - cumsum[lidx])
-		mean2 := sum2 / n2
-
-		sb := ((n1 * n2) / (n1 + n2)) * (mean1 - mean2) * (mean1 - mean2)
-		if maxsb < sb {
+		if sb := sp.score(l); maxsb < sb {
 			maxsb = sb
 			maxsbIdx = l
-
-			// The variances are calculated only if needed to
-			// reduce the math in the main loop
-			var1 := (cumsumsq[lidx] - (cumsum[lidx]*cumsum[lidx])/(n1)) / (n1 - 1)
-			var2 := ((sumsq - cumsumsq[lidx]) - (sum2*sum2)/(n2)) / (n2 - 1)
-
-			before.mean, before.variance, before.n = mean1, var1, l
-			after.mean, after.variance, after.n = mean2, var2, n-l
 		}
 	}
 
-	var conf float64
-	if before.n > 0 {
+	// The variances are calculated only at the best split to reduce the
+	// math in the main loop
+	var before, after Stats
+	var conf, rho float64
+	if maxsbIdx > 0 {
 		// we found a difference
-		conf = onlinestats.Welch(before, after)
+		before, after = sp.stats(maxsbIdx)
+		conf, rho = d.confidence(window, maxsbIdx, before, after)
 	}
 
+	d.logCheck(n, maxsbIdx, maxsb, conf)
+
 	// not above our threshold
 	if conf <= d.MinConfidence {
-		return nil
+		return nil, maxsb, conf
+	}
+
+	// The within-class scatter is the total scatter minus sb, so under a
+	// Gaussian model the log-likelihood ratio between the best position and
+	// any other is (maxsb - sb) / 2*variance.  Positions where twice that
+	// is below the 95% point of the chi-squared distribution with one
+	// degree of freedom form the confidence interval.
+	sse := (sp.sumsq - sp.sum*sp.sum/float64(n)) - maxsb
+	variance := sse / float64(n-2)
+
+	lo, hi := maxsbIdx, maxsbIdx
+	if variance > 0 {
+		for lo > minSampleSize && (maxsb-sp.score(lo-1))/variance < chiSquared95 {
+			lo--
+		}
+		for hi < n-minSampleSize && (maxsb-sp.score(hi+1))/variance < chiSquared95 {
+			hi++
+		}
 	}
 
 	cp := &ChangePoint{
 		Index:      maxsbIdx,
+		IndexLow:   lo,
+		IndexHigh:  hi,
 		Difference: after.Mean() - before.Mean(),
 		Confidence: conf,
+		Score:      maxsb,
 		Before:     before,
 		After:      after,
+		Offset:     maxsbIdx,
+		Lag:        n - maxsbIdx,
+
+		Autocorrelation: rho,
+		ZeroVariance:    zeroVariance(before) && zeroVariance(after),
 	}
 
-	return cp
+	if d.SegmentTrends {
+		cp.BeforeTrend.Tau, cp.BeforeTrend.P = trend.MannKendall(window[:maxsbIdx])
+		cp.AfterTrend.Tau, cp.AfterTrend.P = trend.MannKendall(window[maxsbIdx:])
+	}
+
+	return cp, maxsb, conf
+}
+
+// splits holds the cumulative sums of a window, from which the score and
+// the statistics of splitting it at any index are computed in constant time
+type splits struct {
+	// cumsum contains the cumulative sum of all elements <= i
+	// cumsumsq contains the cumulative sum of squares of all elements <= i
+	// TODO(dgryski): move this to a move numerically stable algorithm
+	cumsum, cumsumsq []float64
+
+	// The sums are of the items less the first, so that their rounding
+	// error depends on the spread of the window and not on its level.
+	shift, sum, sumsq, roundoff float64
+}
+
+func newSplits(window []float64) *splits {
+	n := len(window)
+	sp := &splits{cumsum: make([]float64, n), cumsumsq: make([]float64, n)}
+
+	if n > 0 {
+		sp.shift = window[0]
+	}
+
+	for i, v := range window {
+		v -= sp.shift
+		sp.sum += v
+		sp.sumsq += v * v
+		sp.cumsum[i] = sp.sum
+		sp.cumsumsq[i] = sp.sumsq
+	}
+	sp.roundoff = sumsRoundoff(n, sp.sumsq)
+
+	return sp
+}
+
+// score returns the between-class scatter of splitting the window before l
+func (sp *splits) score(l int) float64 {
+	n1, n2 := float64(l), float64(len(sp.cumsum)-l)
+	mean1 := sp.cumsum[l-1] / n1
+	mean2 := (sp.sum - sp.cumsum[l-1]) / n2
+	return ((n1 * n2) / (n1 + n2)) * (mean1 - mean2) * (mean1 - mean2)
+}
+
+// stats returns the statistics of the samples before and after l
+func (sp *splits) stats(l int) (before, after Stats) {
+	n := len(sp.cumsum)
+	lidx := l - 1
+	n1 := float64(l)
+	mean1 := sp.cumsum[lidx] / n1
+
+	n2 := float64(n - l)
+	sum2 := (sp.sum - sp.cumsum[lidx])
+	mean2 := sum2 / n2
+
+	var1 := (sp.cumsumsq[lidx] - (sp.cumsum[lidx]*sp.cumsum[lidx])/(n1)) / (n1 - 1)
+	var2 := ((sp.sumsq - sp.cumsumsq[lidx]) - (sum2*sum2)/(n2)) / (n2 - 1)
+
+	before = Stats{mean: mean1 + sp.shift, variance: var1, n: l, roundoff: sp.roundoff / (n1 - 1)}
+	after = Stats{mean: mean2 + sp.shift, variance: var2, n: n - l, roundoff: sp.roundoff / (n2 - 1)}
+	return before, after
+}
+
+// confidence returns the t-test confidence of the split of window at l
+// into before and after, and the lag-1 autocorrelation used to adjust the
+// sample sizes if AdjustAutocorrelation is set
+func (d *Detector) confidence(window []float64, l int, before, after Stats) (conf, rho float64) {
+	if d.AdjustAutocorrelation {
+		rho = autocorrelation(window, l, before.mean, after.mean)
+		before.n, after.n = effectiveLen(before.n, rho), effectiveLen(after.n, rho)
+	}
+	return welch(before, after), rho
 }
 
 // Stream monitors a stream of floats for changes
 type Stream struct {
+	// mu serializes pushes with SetParams
+	mu sync.Mutex
+
 	windowSize int
 	blockSize  int
 
@@ -169,47 +411,455 @@ type Stream struct {
 
 	items int
 
+	// readyAt is the number of items after which the window holds only
+	// pushed data and can be checked
+	readyAt int
+
 	buffer []float64
 	bufidx int
 
+	// weights and wbuffer parallel data and buffer; they are only
+	// allocated once an item with a weight other than 1 is pushed
+	weights []float64
+	wbuffer []float64
+
 	detector *Detector
+
+	// NonFinite is how NaN and infinite items are handled
+	NonFinite NonFinitePolicy
+
+	lastFinite         float64
+	finiteLo, finiteHi float64
+	finiteSeen         bool
+	err                error
+
+	// Counter causes pushed items to be treated as the values of a
+	// monotonically increasing counter.  Detection is run on the increase
+	// between successive items instead, so the first item pushed only
+	// primes the counter.
+	Counter bool
+
+	// CounterMax is the value at which the counter wraps around.  If zero, a
+	// decreasing counter is treated as having been reset.
+	CounterMax float64
+
+	counterLast float64
+	counterSeen bool
+
+	// Expected, if set, returns the value predicted by a baseline model
+	// for the n'th item pushed, counting from zero.  Detection is run on the
+	// residuals, actual minus expected, so that seasonality or growth that
+	// is already modelled elsewhere is not reported as a change.
+	Expected func(n int) float64
+
+	pushed int
+
+	// Season, if non-zero, is the number of items in a seasonal period, such
+	// as a week.  Detection is run on the difference between each item and
+	// the item one period earlier, so changes that also happened at the
+	// same point in the previous period, such as recurring batch jobs, are
+	// not reported.  The first Season items only prime the stream.
+	Season int
+
+	season    []float64
+	seasonIdx int
+
+	// AR, if set, are the coefficients of an autoregressive model of the
+	// series, most recent lag first.  Detection is run on the innovations,
+	// each item minus its prediction from the previous len(AR) items, so
+	// that autocorrelated series do not trigger spurious changes.  The
+	// first len(AR) items only prime the model.  See FitAR1.
+	AR []float64
+
+	arHistory []float64
+
+	// Smooth, if greater than 1, is the number of items in a trailing
+	// moving average applied before detection, which reduces the noise
+	// level by a factor of sqrt(Smooth).  Only past items are averaged, so
+	// no item waits for later ones, but a step is spread over Smooth items
+	// and change points are found about (Smooth-1)/2 items after the
+	// change.  A centered average has no such bias but needs Smooth/2
+	// items after each one, so it belongs in offline analysis; see the
+	// offline package.  Smoothed items are autocorrelated, so consider
+	// setting AdjustAutocorrelation on the detector.  The first Smooth-1
+	// items only prime the average.
+	Smooth int
+
+	smooth    []float64
+	smoothIdx int
+	smoothSum float64
+
+	// Aggregate is the number of items combined with AggregateFunc to
+	// produce each value in the window.  Values of 0 or 1 disable
+	// aggregation.  Offsets and lags of change points are reported in
+	// aggregated values.
+	Aggregate int
+
+	// AggregateFunc combines Aggregate items into a single value.  It may
+	// reorder its argument.  If nil, Mean is used.
+	AggregateFunc func([]float64) float64
+
+	aggregate       []float64
+	aggregateWeight float64
+
+	// Transform, if set, is applied to each item before it is added to the window
+	Transform func(float64) float64
+
+	// CaptureWindow causes a copy of the window to be included in each
+	// change point found, for use in alerts and logs
+	CaptureWindow bool
+
+	// Confirm is the number of items to wait after a change is detected
+	// before re-verifying that the new level has persisted.  If non-zero,
+	// changes are first reported with Tentative set and reported again,
+	// with Tentative cleared, once confirmed.  Unconfirmed changes are
+	// dropped.  Confirmation is limited to the items still in the window.
+	Confirm int
+
+	pending   *ChangePoint
+	pendingAt int
+
+	// Muted, if set, is called before each check; while it returns true
+	// items are still added to the window but no change points are reported.
+	Muted func() bool
+
+	mutedUntil time.Time
+
+	// Logger, if set, receives debug records of items skipped while the
+	// window fills, muted checks and confirmations.  Set the Logger of
+	// the stream's Detector as well to log the scores of each check.
+	Logger *slog.Logger
+
+	// History is the number of recent change points kept for Recent.  If
+	// zero, none are kept.
+	History int
+
+	history []ChangePoint
+
+	// MinInterval is the minimum time between reported change points.
+	// Change points found sooner after the last one reported are dropped
+	// and counted in the Suppressed field of the next one, to protect
+	// alerting systems from a flapping series.  If zero, every change point
+	// is reported.
+	MinInterval time.Duration
+
+	lastReported time.Time
+	suppressed   int
+
+	// Suppressor, if set, is consulted with the detection time before each
+	// change point is reported, and drops those it suppresses
+	Suppressor Suppressor
+
+	// Shadow marks every change point the stream reports as shadow-only,
+	// so a new configuration can run alongside the current one, for example
+	// in a Tee, with its would-be changes recorded and compared but not
+	// alerted on
+	Shadow bool
+
+	// the results of the most recent check, for Snapshot
+	checks                    int
+	lastScore, lastConfidence float64
+	lastChange                *ChangePoint
+
+	// now returns the current time; it can be replaced by tests
+	now func() time.Time
 }
 
-// NewStream constructs a new stream detector
+// NewStream constructs a new stream detector.  It panics if windowSize is not
+// positive or blockSize is not between 1 and windowSize; use
+// NewStreamFromConfig to validate parameters from an untrusted source.
 func NewStream(windowSize int, minSample int, blockSize int, confidence float64) *Stream {
+	if windowSize <= 0 || blockSize <= 0 || blockSize > windowSize {
+		panic(fmt.Sprintf("change: invalid stream window size %d and block size %d", windowSize, blockSize))
+	}
+
 	return &Stream{
 		windowSize: windowSize,
 		blockSize:  blockSize,
 		data:       make([]float64, windowSize),
 		buffer:     make([]float64, blockSize),
+		readyAt:    windowSize,
 
 		detector: &Detector{
 			MinSampleSize: minSample,
 			MinConfidence: confidence,
 		},
+
+		now: time.Now,
 	}
 }
 
 // Push adds a float to the stream and calls the change detector
 func (s *Stream) Push(item float64) *ChangePoint {
+	return s.PushWeighted(item, 1)
+}
+
+// PushWeighted adds a float with the given weight to the stream and calls the
+// change detector.  Weights let items that summarize more data, such as the
+// average latency of a busy interval, count for more.  Items added with Push
+// have a weight of 1.
+func (s *Stream) PushWeighted(item, weight float64) *ChangePoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ready(item, weight) {
+		return nil
+	}
+
+	if s.pending != nil {
+		return s.confirmPending()
+	}
+
+	var cp *ChangePoint
+	if s.weights != nil {
+		cp, s.lastScore, s.lastConfidence = s.detector.checkWeighted(s.data, s.weights)
+	} else {
+		cp, s.lastScore, s.lastConfidence = s.detector.check(s.data)
+	}
+	s.checks++
+
+	if cp != nil {
+		cp = s.found(cp, true)
+	}
+
+	return cp
+}
+
+// ready adds item to the stream, and reports whether the detector should be
+// run on the window
+func (s *Stream) ready(item, weight float64) bool {
+	if weight != 1 && s.weights == nil {
+		s.weights = make([]float64, s.windowSize)
+		s.wbuffer = make([]float64, s.blockSize)
+		for i := range s.weights {
+			s.weights[i] = 1
+		}
+		for i := range s.wbuffer {
+			s.wbuffer[i] = 1
+		}
+	}
+
+	if !s.add(item, weight) {
+		return false
+	}
+
+	if s.muted() {
+		s.debug("stream muted", "items", s.items)
+		return false
+	}
+
+	return true
+}
+
+// found returns cp, a change point found in the window, as it should be
+// reported.  If pend is set and Confirm is in use it becomes the pending
+// change point.
+func (s *Stream) found(cp *ChangePoint, pend bool) *ChangePoint {
+	cp.Offset = s.items - s.windowSize + cp.Index
+	if s.CaptureWindow {
+		cp.Window = append([]float64(nil), s.data...)
+	}
+
+	s.debug("change point found", "offset", cp.Offset, "difference", cp.Difference, "confidence", cp.Confidence)
+
+	if s.Confirm > 0 && pend {
+		cp.Tentative = true
+		pending := *cp
+		s.pending, s.pendingAt = &pending, s.items
+	}
+
+	return s.emit(cp)
+}
+
+// confirmPending returns the pending change point once it is confirmed, or
+// nil while waiting for Confirm items or if it is not
+func (s *Stream) confirmPending() *ChangePoint {
+	if s.items-s.pendingAt < s.Confirm {
+		return nil
+	}
+	if cp := s.confirm(); cp != nil {
+		return s.emit(cp)
+	}
+	return nil
+}
+
+// confirm checks whether the level after the pending change point has
+// persisted in the items pushed since it was detected.
+func (s *Stream) confirm() *ChangePoint {
+	cp := s.pending
+	s.pending = nil
+
+	k := s.items - s.pendingAt
+	if k > s.windowSize {
+		k = s.windowSize
+	}
+	since := newStats(s.data[s.windowSize-k:])
+
+	if (since.mean-cp.Before.mean)*cp.Difference <= 0 {
+		s.debug("change point not confirmed", "offset", cp.Offset, "mean", since.mean)
+		return nil
+	}
+
+	if conf := welch(cp.Before, since); conf <= s.detector.MinConfidence {
+		s.debug("change point not confirmed", "offset", cp.Offset, "mean", since.mean, "confidence", conf)
+		return nil
+	}
+
+	s.debug("change point confirmed", "offset", cp.Offset)
+
+	cp.Tentative = false
+	cp.Lag = s.items - cp.Offset
+
+	return cp
+}
+
+// Mute suppresses change points until the given time, for example during a
+// planned deploy or maintenance.  Items are still added to the window so
+// detection is effective as soon as the stream is unmuted.  Muting until the
+// zero time unmutes the stream.
+func (s *Stream) Mute(until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mutedUntil = until
+}
+
+func (s *Stream) muted() bool {
+	if s.now().Before(s.mutedUntil) {
+		return true
+	}
+	return s.Muted != nil && s.Muted()
+}
+
+// Prime fills the window from historical data without running the change
+// detector, so that detection is effective immediately after a restart
+// instead of only after windowSize items have been pushed.
+func (s *Stream) Prime(history []float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range history {
+		s.add(item, 1)
+	}
+}
+
+// add adds item to the stream, and reports whether the window is full and
+// should be checked.
+func (s *Stream) add(item, weight float64) bool {
+	item, weight, ok := s.preprocess(item, weight)
+	if !ok {
+		return false
+	}
+
 	s.buffer[s.bufidx] = item
+	if s.weights != nil {
+		s.wbuffer[s.bufidx] = weight
+	}
 	s.bufidx++
 	s.items++
 
 	if s.bufidx < s.blockSize {
-		return nil
+		return false
+	}
+
+	s.flush()
+
+	if s.items < s.readyAt {
+		s.debug("stream warming up", "items", s.items, "window", s.windowSize)
+		return false
 	}
 
-	copy(s.data[0:], s.data[s.blockSize:])
-	copy(s.data[s.windowSize-s.blockSize:], s.buffer)
+	return true
+}
+
+// flush shifts the buffered items into the window
+func (s *Stream) flush() {
+	k := s.bufidx
+	copy(s.data[0:], s.data[k:])
+	copy(s.data[s.windowSize-k:], s.buffer[:k])
+	if s.weights != nil {
+		copy(s.weights[0:], s.weights[k:])
+		copy(s.weights[s.windowSize-k:], s.wbuffer[:k])
+	}
 	s.bufidx = 0
+}
 
-	if s.items < s.windowSize {
-		return nil
+// preprocess runs item through the non-finite, counter, residual, seasonal, pre-whitening, smoothing, aggregation and transform stages
+// of the stream.  It returns false if no value should be added to the window
+// yet.  Aggregated items have the total weight of the items they combine.
+func (s *Stream) preprocess(item, weight float64) (float64, float64, bool) {
+	if finite(item) {
+		if !s.finiteSeen {
+			s.finiteLo, s.finiteHi = item, item
+		}
+		s.lastFinite, s.finiteSeen = item, true
+		s.finiteLo, s.finiteHi = min(s.finiteLo, item), max(s.finiteHi, item)
+	} else {
+		var ok bool
+		if item, ok = s.nonFinite(item); !ok {
+			s.pushed++
+			return 0, 0, false
+		}
+	}
+
+	n := s.pushed
+	s.pushed++
+
+	if s.Counter {
+		prev, seen := s.counterLast, s.counterSeen
+		s.counterLast, s.counterSeen = item, true
+		if !seen {
+			return 0, 0, false
+		}
+		item = counterRate(prev, item, s.CounterMax)
+	}
+
+	if s.Expected != nil {
+		item -= s.Expected(n)
+	}
+
+	if s.Season > 0 {
+		var ok bool
+		if item, ok = s.deseason(item); !ok {
+			return 0, 0, false
+		}
+	}
+
+	if len(s.AR) > 0 {
+		var ok bool
+		if item, ok = s.prewhiten(item); !ok {
+			return 0, 0, false
+		}
+	}
+
+	if s.Smooth > 1 {
+		var ok bool
+		if item, ok = s.trailingMean(item); !ok {
+			return 0, 0, false
+		}
+	}
+
+	if s.Aggregate > 1 {
+		s.aggregate = append(s.aggregate, item)
+		s.aggregateWeight += weight
+		if len(s.aggregate) < s.Aggregate {
+			return 0, 0, false
+		}
+
+		f := s.AggregateFunc
+		if f == nil {
+			f = Mean
+		}
+		item, weight = f(s.aggregate), s.aggregateWeight
+		s.aggregate, s.aggregateWeight = s.aggregate[:0], 0
+	}
+
+	if s.Transform != nil {
+		item = s.Transform(item)
 	}
 
-	return s.detector.Check(s.data)
+	return item, weight, true
 }
 
-// Window returns the current data window.  This should be treated as read-only
-func (s *Stream) Window() []float64 { return s.data }
+// Detector returns the detector run on the window, so its options can be set
+func (s *Stream) Detector() *Detector { return s.detector }
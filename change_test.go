@@ -1,6 +1,11 @@
 package change
 
-import "testing"
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
 
 func TestDetectChange(t *testing.T) {
 
@@ -38,3 +43,320 @@ func TestDetectChange(t *testing.T) {
 		}
 	}
 }
+
+func TestStreamOffset(t *testing.T) {
+
+	s := NewStream(40, 5, 5, 0.95)
+
+	var r *ChangePoint
+	for i := 0; i < 100 && r == nil; i++ {
+		v := 1.0
+		if i >= 60 {
+			v = 2.0
+		}
+		r = s.Push(v)
+	}
+
+	if r == nil {
+		t.Fatalf("Stream failed to detect change")
+	}
+
+	if r.Offset != 60 {
+		t.Errorf("Stream offset=%d, wanted 60", r.Offset)
+	}
+
+	if r.Lag != r.After.Len() {
+		t.Errorf("Stream lag=%d, wanted %d", r.Lag, r.After.Len())
+	}
+
+	if r.Window != nil {
+		t.Errorf("Stream captured window without CaptureWindow")
+	}
+}
+
+func TestStreamCaptureWindow(t *testing.T) {
+
+	s := NewStream(40, 5, 5, 0.95)
+	s.CaptureWindow = true
+
+	var r *ChangePoint
+	for i := 0; i < 100 && r == nil; i++ {
+		v := 1.0
+		if i >= 60 {
+			v = 2.0
+		}
+		r = s.Push(v)
+	}
+
+	if r == nil || len(r.Window) != 40 || r.Window[r.Index] != 2 || r.Window[r.Index-1] != 1 {
+		t.Fatalf("Stream returned %v, wanted captured window", r)
+	}
+
+	// the captured window must not change as more items are pushed
+	for i := 0; i < 5; i++ {
+		s.Push(3)
+	}
+	if r.Window[len(r.Window)-1] != 2 {
+		t.Errorf("captured window was modified by later pushes")
+	}
+}
+
+func TestEffectSizeScaleInvariant(t *testing.T) {
+
+	w := []float64{1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 4, 5, 4, 5, 4, 5, 4, 5, 4, 5}
+	scaled := make([]float64, len(w))
+	for i, v := range w {
+		scaled[i] = 1000*v + 50
+	}
+
+	detector := Detector{MinSampleSize: 5}
+
+	r1 := detector.Check(w)
+	r2 := detector.Check(scaled)
+
+	if r1 == nil || r2 == nil {
+		t.Fatalf("Check failed to find change: %v %v", r1, r2)
+	}
+
+	if r1.Index != r2.Index {
+		t.Errorf("Check index=%d for scaled data, wanted %d", r2.Index, r1.Index)
+	}
+
+	if d1, d2 := r1.EffectSize(), r2.EffectSize(); math.Abs(d1-d2) > 1e-9 {
+		t.Errorf("EffectSize=%f for scaled data, wanted %f", d2, d1)
+	}
+}
+
+func TestEffectSizeZeroVariance(t *testing.T) {
+
+	var tests = []struct {
+		before, after float64
+		want          float64
+	}{
+		{1, 1, 0},
+		{0.1, 0.3, math.Inf(1)},
+		{0.3, 0.1, math.Inf(-1)},
+		{1e9, 1e9, 0},
+	}
+
+	for _, tt := range tests {
+		w := make([]float64, 20)
+		for i := range w {
+			w[i] = tt.before
+			if i >= 10 {
+				w[i] = tt.after
+			}
+		}
+
+		cp := ChangePoint{Before: newStats(w[:10]), After: newStats(w[10:])}
+		cp.Difference = cp.After.mean - cp.Before.mean
+		if got := cp.EffectSize(); got != tt.want {
+			t.Errorf("EffectSize(%v to %v)=%v, wanted %v", tt.before, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestStreamPrime(t *testing.T) {
+
+	history := make([]float64, 100)
+	for i := range history {
+		history[i] = 1
+	}
+
+	s := NewStream(40, 5, 5, 0.95)
+	s.Prime(history)
+
+	// the first block after priming should already be checked
+	var r *ChangePoint
+	for i := 0; i < 5; i++ {
+		r = s.Push(2)
+	}
+
+	if r == nil || r.Offset != 100 {
+		t.Errorf("Stream after Prime returned %v, wanted change at 100", r)
+	}
+}
+
+func TestStreamMuteConcurrent(t *testing.T) {
+
+	// run with -race: Mute may be called while another goroutine pushes
+	s := NewStream(40, 5, 5, 0.99)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			s.Push(float64(i % 7))
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		s.Mute(time.Now().Add(time.Duration(i) * time.Millisecond))
+	}
+	<-done
+}
+
+func TestStreamMute(t *testing.T) {
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewStream(40, 5, 5, 0.95)
+	s.now = func() time.Time { return now }
+	s.Mute(now.Add(time.Hour))
+
+	var found bool
+	for i := 0; i < 70; i++ {
+		v := 1.0
+		if i >= 60 {
+			v = 2.0
+		}
+		if s.Push(v) != nil {
+			found = true
+		}
+	}
+
+	if found {
+		t.Errorf("muted Stream reported a change")
+	}
+
+	s.Mute(time.Time{})
+
+	var r *ChangePoint
+	for i := 0; i < 5; i++ {
+		r = s.Push(2)
+	}
+
+	if r == nil {
+		t.Errorf("unmuted Stream failed to report change")
+	}
+
+	s.Muted = func() bool { return true }
+	for i := 0; i < 5; i++ {
+		r = s.Push(2)
+	}
+	if r != nil {
+		t.Errorf("Stream with Muted hook reported a change")
+	}
+}
+
+func TestStreamConfirm(t *testing.T) {
+
+	var tests = []struct {
+		name      string
+		after     float64 // the level after the tentative change
+		confirmed bool
+	}{
+		{"persistent", 2, true},
+		{"transient", 1, false},
+	}
+
+	for _, tt := range tests {
+		s := NewStream(40, 5, 5, 0.95)
+		s.Confirm = 10
+
+		var tentative, confirmed *ChangePoint
+		for i := 0; i < 90; i++ {
+			v := 1.0 + 0.1*float64(i%2)
+			if i >= 60 && i < 65 {
+				v = 2
+			} else if i >= 65 {
+				v = tt.after + 0.1*float64(i%2)
+			}
+
+			r := s.Push(v)
+			switch {
+			case r == nil:
+			case r.Tentative && tentative == nil:
+				tentative = r
+			case !r.Tentative && confirmed == nil:
+				confirmed = r
+			}
+		}
+
+		if tentative == nil {
+			t.Errorf("%s: no tentative change reported", tt.name)
+			continue
+		}
+
+		if (confirmed != nil) != tt.confirmed {
+			t.Errorf("%s: confirmed=%v, wanted %v", tt.name, confirmed, tt.confirmed)
+		}
+
+		if confirmed != nil && (confirmed.Offset != tentative.Offset || confirmed.Lag <= tentative.Lag) {
+			t.Errorf("%s: confirmed %v does not match tentative %v", tt.name, confirmed, tentative)
+		}
+	}
+}
+
+func TestSegmentTrends(t *testing.T) {
+
+	var w []float64
+	for i := 0; i < 40; i++ {
+		if i < 20 {
+			w = append(w, float64(i%3))
+		} else {
+			w = append(w, 10+float64(i))
+		}
+	}
+
+	detector := Detector{MinSampleSize: 5, SegmentTrends: true}
+
+	r := detector.Check(w)
+	if r == nil {
+		t.Fatalf("Check failed to find change")
+	}
+
+	if r.BeforeTrend.P < 0.05 {
+		t.Errorf("before segment trend=%+v, wanted no trend", r.BeforeTrend)
+	}
+
+	if r.AfterTrend.Tau != 1 || r.AfterTrend.P > 0.05 {
+		t.Errorf("after segment trend=%+v, wanted increasing trend", r.AfterTrend)
+	}
+}
+
+func TestIndexInterval(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	var tests = []struct {
+		name  string
+		shift float64
+	}{
+		{"sharp", 10},
+		{"vague", 0.8},
+	}
+
+	var widths []int
+
+	for _, tt := range tests {
+		var w []float64
+		for i := 0; i < 200; i++ {
+			v := rnd.NormFloat64()
+			if i >= 100 {
+				v += tt.shift
+			}
+			w = append(w, v)
+		}
+
+		detector := Detector{MinSampleSize: 10, MinConfidence: 0.95}
+		r := detector.Check(w)
+		if r == nil {
+			t.Fatalf("%s: Check failed to find change", tt.name)
+		}
+
+		if r.IndexLow > r.Index || r.IndexHigh < r.Index {
+			t.Errorf("%s: interval [%d,%d] does not contain index %d", tt.name, r.IndexLow, r.IndexHigh, r.Index)
+		}
+
+		if r.IndexLow > 100 || r.IndexHigh < 100 {
+			t.Errorf("%s: interval [%d,%d] does not contain the true change", tt.name, r.IndexLow, r.IndexHigh)
+		}
+
+		widths = append(widths, r.IndexHigh-r.IndexLow)
+	}
+
+	if widths[0] > 0 || widths[1] < 2 {
+		t.Errorf("interval widths=%v, wanted a sharp and a wider interval", widths)
+	}
+}
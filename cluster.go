@@ -0,0 +1,85 @@
+package change
+
+import (
+	"sort"
+	"time"
+)
+
+// Member is a change point belonging to a Cluster
+type Member struct {
+	// Series is the index of the series the change point was found in
+	Series int
+
+	ChangePoint
+}
+
+// Cluster is a group of change points from several series that occurred at
+// roughly the same place.
+type Cluster struct {
+	// Start and End are the offsets of the first and last members
+	Start, End int
+
+	// Members are the change points in the cluster, ordered by offset
+	Members []Member
+}
+
+// Series returns the number of distinct series with a change point in the cluster
+func (c *Cluster) Series() int {
+	seen := make(map[int]bool)
+	for _, m := range c.Members {
+		seen[m.Series] = true
+	}
+	return len(seen)
+}
+
+// ClusterPoints groups the change points found in several series.  Change points
+// whose offsets are within tolerance of their neighbours are placed in the
+// same cluster, so a cluster can be used to report that "23 of 40 series
+// changed at offset 1042".  Clusters are returned in order of offset.
+func ClusterPoints(changesPerSeries [][]ChangePoint, tolerance int) []Cluster {
+	return cluster(changesPerSeries, func(a, b *ChangePoint) bool {
+		return b.Offset-a.Offset <= tolerance
+	}, func(a, b *ChangePoint) bool {
+		return a.Offset < b.Offset
+	})
+}
+
+// ClusterTimes is like ClusterPoints but groups change points found by a
+// TimeStream using their timestamps.
+func ClusterTimes(changesPerSeries [][]ChangePoint, tolerance time.Duration) []Cluster {
+	return cluster(changesPerSeries, func(a, b *ChangePoint) bool {
+		return b.Time.Sub(a.Time) <= tolerance
+	}, func(a, b *ChangePoint) bool {
+		return a.Time.Before(b.Time)
+	})
+}
+
+func cluster(changesPerSeries [][]ChangePoint, near, less func(a, b *ChangePoint) bool) []Cluster {
+
+	var members []Member
+	for i, changes := range changesPerSeries {
+		for _, cp := range changes {
+			members = append(members, Member{i, cp})
+		}
+	}
+
+	sort.SliceStable(members, func(i, j int) bool { return less(&members[i].ChangePoint, &members[j].ChangePoint) })
+
+	var clusters []Cluster
+	for i := 0; i < len(members); {
+		j := i + 1
+		for j < len(members) && near(&members[j-1].ChangePoint, &members[j].ChangePoint) {
+			j++
+		}
+
+		clusters = append(clusters, Cluster{
+			Start:   members[i].Offset,
+			End:     members[j-1].Offset,
+			Members: members[i:j:j],
+		})
+
+		i = j
+	}
+
+	return clusters
+}
@@ -0,0 +1,47 @@
+package change
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClusterPoints(t *testing.T) {
+
+	changes := [][]ChangePoint{
+		{{Offset: 100}, {Offset: 500}},
+		{{Offset: 103}},
+		{{Offset: 98}, {Offset: 300}},
+	}
+
+	clusters := ClusterPoints(changes, 5)
+
+	if len(clusters) != 3 {
+		t.Fatalf("ClusterPoints returned %d clusters, wanted 3: %v", len(clusters), clusters)
+	}
+
+	c := clusters[0]
+	if c.Start != 98 || c.End != 103 || c.Series() != 3 || len(c.Members) != 3 {
+		t.Errorf("first cluster=%+v, wanted 98-103 with 3 series", c)
+	}
+
+	if clusters[1].Start != 300 || clusters[1].Series() != 1 || clusters[1].Members[0].Series != 2 {
+		t.Errorf("second cluster=%+v, wanted 300 from series 2", clusters[1])
+	}
+}
+
+func TestClusterTimes(t *testing.T) {
+
+	t0 := time.Date(2024, 1, 1, 14, 32, 0, 0, time.UTC)
+
+	changes := [][]ChangePoint{
+		{{Time: t0}},
+		{{Time: t0.Add(20 * time.Second)}},
+		{{Time: t0.Add(time.Hour)}},
+	}
+
+	clusters := ClusterTimes(changes, time.Minute)
+
+	if len(clusters) != 2 || clusters[0].Series() != 2 || clusters[1].Series() != 1 {
+		t.Errorf("ClusterTimes=%+v, wanted clusters of 2 and 1 series", clusters)
+	}
+}
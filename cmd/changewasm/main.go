@@ -0,0 +1,59 @@
+//go:build js && wasm
+
+// Command changewasm exposes the change detector to JavaScript
+/*
+Build with
+
+	GOOS=js GOARCH=wasm go build -o change.wasm ./cmd/changewasm
+
+and load change.wasm with the wasm_exec.js shipped with Go.  Once running it
+defines a global function
+
+	changeDetect(data, minSampleSize, minConfidence)
+
+which takes an array of numbers and returns an array of the change points
+found in it, each an object with index, difference and confidence fields.
+*/
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/offline"
+)
+
+func main() {
+	js.Global().Set("changeDetect", js.FuncOf(detect))
+
+	// keep the exported function callable
+	select {}
+}
+
+func detect(this js.Value, args []js.Value) any {
+	if len(args) != 3 {
+		return js.Global().Get("Error").New("changeDetect: wanted data, minSampleSize and minConfidence")
+	}
+
+	data := args[0]
+	series := make([]float64, data.Length())
+	for i := range series {
+		series[i] = data.Index(i).Float()
+	}
+
+	d := &change.Detector{
+		MinSampleSize: args[1].Int(),
+		MinConfidence: args[2].Float(),
+	}
+
+	var changes []any
+	for cp := range offline.Changes(series, d) {
+		changes = append(changes, map[string]any{
+			"index":      cp.Index,
+			"difference": cp.Difference,
+			"confidence": cp.Confidence,
+		})
+	}
+
+	return js.ValueOf(changes)
+}
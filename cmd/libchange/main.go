@@ -0,0 +1,76 @@
+//go:build cgo
+
+// Command libchange builds the change detector as a C shared library
+/*
+Build with
+
+	go build -buildmode=c-shared -o libchange.so ./cmd/libchange
+
+which also writes libchange.h.  The library exports a single function
+
+	int change_detect(double *data, int n, change_params params, change_point *out, int max_out);
+
+which finds up to max_out change points in data[0:n] by binary segmentation,
+as offline.Changes does, writes them to out in order of index, and returns
+the number found, or -1 if the arguments are invalid.  This lets Python, R
+and other languages with a C FFI run exactly the same implementation as Go
+services.  The c-shared build mode requires cgo.
+*/
+package main
+
+/*
+typedef struct {
+	int    min_sample_size;
+	double min_confidence;
+} change_params;
+
+typedef struct {
+	int    index;
+	double difference;
+	double confidence;
+} change_point;
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/offline"
+)
+
+//export change_detect
+func change_detect(data *C.double, n C.int, params C.change_params, out *C.change_point, maxOut C.int) C.int {
+	if n < 0 || maxOut < 0 || (n > 0 && data == nil) || (maxOut > 0 && out == nil) {
+		return -1
+	}
+
+	series := make([]float64, int(n))
+	for i, v := range unsafe.Slice(data, int(n)) {
+		series[i] = float64(v)
+	}
+
+	d := &change.Detector{
+		MinSampleSize: int(params.min_sample_size),
+		MinConfidence: float64(params.min_confidence),
+	}
+
+	points := unsafe.Slice(out, int(maxOut))
+
+	var found int
+	for cp := range offline.Changes(series, d) {
+		if found == len(points) {
+			break
+		}
+		points[found] = C.change_point{
+			index:      C.int(cp.Index),
+			difference: C.double(cp.Difference),
+			confidence: C.double(cp.Confidence),
+		}
+		found++
+	}
+
+	return C.int(found)
+}
+
+func main() {}
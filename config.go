@@ -0,0 +1,50 @@
+package change
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidConfig is returned by Config.Validate for unusable parameters
+var ErrInvalidConfig = errors.New("change: invalid config")
+
+// Config is the set of parameters used to construct a Stream.  It can be
+// loaded from JSON or YAML configuration files.
+type Config struct {
+	WindowSize    int     `json:"window_size" yaml:"window_size"`
+	MinSampleSize int     `json:"min_sample_size" yaml:"min_sample_size"`
+	BlockSize     int     `json:"block_size" yaml:"block_size"`
+	MinConfidence float64 `json:"min_confidence" yaml:"min_confidence"`
+}
+
+// Validate reports whether a stream constructed from c can detect changes.
+// A MinSampleSize of zero means DefaultMinSampleSize.
+func (c Config) Validate() error {
+	minSample := c.MinSampleSize
+	if minSample == 0 {
+		minSample = DefaultMinSampleSize
+	}
+
+	switch {
+	case c.WindowSize <= 0:
+		return fmt.Errorf("%w: window size %d must be positive", ErrInvalidConfig, c.WindowSize)
+	case c.BlockSize <= 0 || c.BlockSize > c.WindowSize:
+		return fmt.Errorf("%w: block size %d must be between 1 and the window size %d", ErrInvalidConfig, c.BlockSize, c.WindowSize)
+	case minSample < 2:
+		return fmt.Errorf("%w: minimum sample size %d must be at least 2", ErrInvalidConfig, c.MinSampleSize)
+	case 2*minSample > c.WindowSize:
+		return fmt.Errorf("%w: window size %d is too small for minimum sample size %d", ErrInvalidConfig, c.WindowSize, minSample)
+	case !(c.MinConfidence >= 0 && c.MinConfidence < 1):
+		return fmt.Errorf("%w: minimum confidence %v must be in [0, 1)", ErrInvalidConfig, c.MinConfidence)
+	}
+
+	return nil
+}
+
+// NewStreamFromConfig validates c and constructs a new stream detector from it
+func NewStreamFromConfig(c Config) (*Stream, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return NewStream(c.WindowSize, c.MinSampleSize, c.BlockSize, c.MinConfidence), nil
+}
@@ -0,0 +1,58 @@
+package change
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+
+	var tests = []struct {
+		c     Config
+		valid bool
+	}{
+		{Config{40, 5, 5, 0.95}, true},
+		{Config{60, 0, 1, 0.95}, true}, // default minimum sample size
+		{Config{40, 0, 1, 0.95}, false},
+		{Config{0, 5, 5, 0.95}, false},
+		{Config{40, 5, 0, 0.95}, false},
+		{Config{40, 5, 50, 0.95}, false},
+		{Config{40, 1, 5, 0.95}, false},
+		{Config{40, 25, 5, 0.95}, false},
+		{Config{40, 5, 5, 1}, false},
+		{Config{40, 5, 5, -0.5}, false},
+		{Config{40, 5, 5, math.NaN()}, false},
+	}
+
+	for _, tt := range tests {
+		err := tt.c.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v)=%v, wanted valid=%v", tt.c, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Validate(%+v)=%v, wanted ErrInvalidConfig", tt.c, err)
+		}
+	}
+}
+
+func TestNewStreamFromConfig(t *testing.T) {
+
+	var c Config
+	if err := json.Unmarshal([]byte(`{"window_size": 40, "min_sample_size": 5, "block_size": 5, "min_confidence": 0.95}`), &c); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if want := (Config{40, 5, 5, 0.95}); c != want {
+		t.Errorf("unmarshal=%+v, wanted %+v", c, want)
+	}
+
+	if s, err := NewStreamFromConfig(c); s == nil || err != nil {
+		t.Errorf("NewStreamFromConfig(%+v)=%v, %v", c, s, err)
+	}
+
+	if _, err := NewStreamFromConfig(Config{}); err == nil {
+		t.Errorf("NewStreamFromConfig accepted the zero config")
+	}
+}
@@ -0,0 +1,153 @@
+// Package otel integrates change detection with OpenTelemetry
+/*
+Processor watches numeric metric streams, such as the number data points of
+OTLP metrics, with a change.Stream for each metric and attribute set.  A
+change found is recorded as an event on the current span and as a log
+record, which the OpenTelemetry slog bridge exports as an OTLP log record.
+The processor does not depend on the metrics SDK: a receiver or exporter
+passes it each data point with Record.
+
+Detector wraps a change.Detector so that each check is recorded as a span,
+and RecordChange attaches a detected change point to the current span as an
+event, so change points show up alongside the traces of the service that
+found them.
+
+The package imports go.opentelemetry.io/otel and go.opentelemetry.io/otel/trace,
+and is tested with v1.46.0 of both.  Only programs that import it depend on
+OpenTelemetry; the core package does not.
+*/
+package otel
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/dgryski/go-change"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EventName is the name of the span event recorded for a change point
+const EventName = "change_point"
+
+// Attributes returns the OpenTelemetry attributes describing a change point found in series
+func Attributes(series string, cp *change.ChangePoint) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("change.series", series),
+		attribute.Int("change.offset", cp.Offset),
+		attribute.Int("change.lag", cp.Lag),
+		attribute.Float64("change.difference", cp.Difference),
+		attribute.Float64("change.confidence", cp.Confidence),
+		attribute.Float64("change.before.mean", cp.Before.Mean()),
+		attribute.Float64("change.after.mean", cp.After.Mean()),
+	}
+}
+
+// RecordChange adds cp as an event on the span in ctx
+func RecordChange(ctx context.Context, series string, cp *change.ChangePoint) {
+	trace.SpanFromContext(ctx).AddEvent(EventName, trace.WithAttributes(Attributes(series, cp)...))
+}
+
+// Detector is a change.Detector whose checks are traced
+type Detector struct {
+	*change.Detector
+
+	// Tracer is used to start a span for each check
+	Tracer trace.Tracer
+
+	// Series names the data being checked in span attributes
+	Series string
+}
+
+// Check runs the wrapped detector on window inside a new span.  A change
+// point found is recorded as an event on that span.
+func (d *Detector) Check(ctx context.Context, window []float64) *change.ChangePoint {
+	ctx, span := d.Tracer.Start(ctx, "change.Check", trace.WithAttributes(
+		attribute.String("change.series", d.Series),
+		attribute.Int("change.window", len(window)),
+	))
+	defer span.End()
+
+	cp := d.Detector.Check(window)
+	if cp != nil {
+		RecordChange(ctx, d.Series, cp)
+		span.SetStatus(codes.Ok, "")
+	}
+
+	return cp
+}
+
+// Processor finds changes in numeric metric streams.  It is safe for
+// concurrent use.
+type Processor struct {
+	// NewStream returns the stream used for a new series
+	NewStream func() *change.Stream
+
+	// Logger, if set, receives a log record for each change point
+	Logger *slog.Logger
+
+	mu      sync.Mutex
+	streams map[seriesKey]*series
+}
+
+// seriesKey identifies a metric stream by its name and attributes
+type seriesKey struct {
+	name  string
+	attrs attribute.Distinct
+}
+
+type series struct {
+	name   string
+	stream *change.Stream
+}
+
+// Record adds v, the value of a data point of the named metric with the given
+// attributes, to its series.  A change point found is recorded as an event on
+// the span in ctx and logged, and returned.
+func (p *Processor) Record(ctx context.Context, name string, attrs attribute.Set, v float64) *change.ChangePoint {
+	s := p.series(name, attrs)
+
+	cp := s.stream.Push(v)
+	if cp == nil {
+		return nil
+	}
+
+	RecordChange(ctx, s.name, cp)
+
+	if p.Logger != nil {
+		kvs := Attributes(s.name, cp)
+		logAttrs := make([]slog.Attr, len(kvs))
+		for i, kv := range kvs {
+			logAttrs[i] = slog.Any(string(kv.Key), kv.Value.AsInterface())
+		}
+		p.Logger.LogAttrs(ctx, slog.LevelWarn, "change point", logAttrs...)
+	}
+
+	return cp
+}
+
+// series returns the series for the metric, creating it if needed
+func (p *Processor) series(name string, attrs attribute.Set) *series {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := seriesKey{name: name, attrs: attrs.Equivalent()}
+	if s, ok := p.streams[key]; ok {
+		return s
+	}
+
+	if p.streams == nil {
+		p.streams = make(map[seriesKey]*series)
+	}
+
+	label := name
+	if attrs.Len() > 0 {
+		label += "{" + attrs.Encoded(attribute.DefaultEncoder()) + "}"
+	}
+
+	s := &series{name: label, stream: p.NewStream()}
+	p.streams[key] = s
+	return s
+}
@@ -0,0 +1,140 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingSpan records the events and status set on it
+type recordingSpan struct {
+	noop.Span
+
+	name   string
+	events []trace.EventConfig
+	status codes.Code
+	ended  bool
+}
+
+func (s *recordingSpan) AddEvent(name string, opts ...trace.EventOption) {
+	if name == EventName {
+		s.events = append(s.events, trace.NewEventConfig(opts...))
+	}
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+func (s *recordingSpan) End(...trace.SpanEndOption)          { s.ended = true }
+
+type recordingTracer struct {
+	embedded.Tracer
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	s := &recordingSpan{name: name}
+	t.spans = append(t.spans, s)
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// step returns n items with a step up by 5 halfway through
+func step(rnd *rand.Rand, n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = rnd.Float64()
+		if i >= n/2 {
+			w[i] += 5
+		}
+	}
+	return w
+}
+
+func TestDetector(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	tracer := &recordingTracer{}
+	d := &Detector{
+		Detector: &change.Detector{MinSampleSize: 10, MinConfidence: 0.999},
+		Tracer:   tracer,
+		Series:   "latency",
+	}
+
+	if cp := d.Check(context.Background(), step(rnd, 100)); cp == nil || cp.Index != 50 {
+		t.Fatalf("Check=%v, wanted a change at 50", cp)
+	}
+
+	noise := make([]float64, 100)
+	for i := range noise {
+		noise[i] = rnd.Float64()
+	}
+	if cp := d.Check(context.Background(), noise); cp != nil {
+		t.Errorf("Check(noise)=%v, wanted no change", cp)
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("started %d spans, wanted 2", len(tracer.spans))
+	}
+
+	found, quiet := tracer.spans[0], tracer.spans[1]
+	if !found.ended || len(found.events) != 1 || found.status != codes.Ok {
+		t.Errorf("span for a change: ended=%v events=%d status=%v, wanted one event and Ok", found.ended, len(found.events), found.status)
+	}
+	if !quiet.ended || len(quiet.events) != 0 || quiet.status != codes.Unset {
+		t.Errorf("span for no change: ended=%v events=%d status=%v, wanted no events", quiet.ended, len(quiet.events), quiet.status)
+	}
+
+	attrs := attribute.NewSet(found.events[0].Attributes()...)
+	if v, ok := attrs.Value("change.series"); !ok || v.AsString() != "latency" {
+		t.Errorf("event change.series=%v, wanted latency", v.AsString())
+	}
+}
+
+func TestProcessor(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	var buf bytes.Buffer
+	p := &Processor{
+		NewStream: func() *change.Stream { return change.NewStream(60, 10, 5, 0.999) },
+		Logger:    slog.New(slog.NewJSONHandler(&buf, nil)),
+	}
+
+	span := &recordingSpan{}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	// the same metric from two hosts; only host a changes
+	a := attribute.NewSet(attribute.String("host", "a"))
+	b := attribute.NewSet(attribute.String("host", "b"))
+
+	var changes int
+	for _, v := range step(rnd, 200) {
+		if p.Record(ctx, "latency", a, v) != nil {
+			changes++
+		}
+		if cp := p.Record(ctx, "latency", b, rnd.Float64()); cp != nil {
+			t.Errorf("change %v found for host b", cp)
+		}
+	}
+
+	if changes == 0 || len(span.events) != changes {
+		t.Fatalf("found %d changes and recorded %d events, wanted the same non-zero number", changes, len(span.events))
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(bytes.SplitN(buf.Bytes(), []byte("\n"), 2)[0], &record); err != nil {
+		t.Fatalf("log record: %v", err)
+	}
+	if record["msg"] != "change point" || record["change.series"] != "latency{host=a}" {
+		t.Errorf("log record=%v, wanted a change point for latency{host=a}", record)
+	}
+}
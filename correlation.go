@@ -0,0 +1,69 @@
+package change
+
+import "math"
+
+// CorrelationStream monitors the correlation between two streams of floats
+// for changes.  The Pearson correlation of each group of pairs is computed,
+// and a Stream runs change detection on its Fisher transform, so a pair of
+// normally coupled metrics that decouple is reported even when neither
+// changes level.
+type CorrelationStream struct {
+	groupSize int
+	n         int
+
+	sx, sy, sxx, syy, sxy float64
+
+	stream *Stream
+}
+
+// maxCorrelation bounds the magnitude of a group's correlation so its
+// Fisher transform is finite
+const maxCorrelation = 0.999999
+
+// NewCorrelationStream constructs a new correlation stream detector.  The
+// correlation is computed over each group of groupSize pairs.  windowSize,
+// minSample and blockSize are as for NewStream, but count groups rather than
+// pairs.
+func NewCorrelationStream(groupSize int, windowSize int, minSample int, blockSize int, confidence float64) *CorrelationStream {
+	return &CorrelationStream{
+		groupSize: groupSize,
+		stream:    NewStream(windowSize, minSample, blockSize, confidence),
+	}
+}
+
+// Push adds a pair of floats to the stream and calls the change detector
+// once a group is complete.  The means of the returned change point are of
+// Fisher transformed correlations; use math.Tanh to recover correlations.
+func (c *CorrelationStream) Push(x, y float64) *ChangePoint {
+	c.n++
+	c.sx += x
+	c.sy += y
+	c.sxx += x * x
+	c.syy += y * y
+	c.sxy += x * y
+
+	if c.n < c.groupSize {
+		return nil
+	}
+
+	r := correlation(float64(c.n), c.sx, c.sy, c.sxx, c.syy, c.sxy)
+	c.n, c.sx, c.sy, c.sxx, c.syy, c.sxy = 0, 0, 0, 0, 0, 0
+
+	return c.stream.Push(math.Atanh(r))
+}
+
+// Stream returns the stream monitoring the transformed correlations
+func (c *CorrelationStream) Stream() *Stream { return c.stream }
+
+// correlation returns the Pearson correlation from the sums of a group of
+// n pairs.  It is zero if either series is constant.
+func correlation(n, sx, sy, sxx, syy, sxy float64) float64 {
+	vx := sxx - sx*sx/n
+	vy := syy - sy*sy/n
+	if vx <= 0 || vy <= 0 {
+		return 0
+	}
+
+	r := (sxy - sx*sy/n) / math.Sqrt(vx*vy)
+	return math.Max(-maxCorrelation, math.Min(maxCorrelation, r))
+}
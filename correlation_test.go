@@ -0,0 +1,59 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCorrelation(t *testing.T) {
+
+	var tests = []struct {
+		x, y []float64
+		want float64
+	}{
+		{[]float64{1, 2, 3}, []float64{2, 4, 6}, maxCorrelation},
+		{[]float64{1, 2, 3}, []float64{6, 4, 2}, -maxCorrelation},
+		{[]float64{1, 2, 3, 4}, []float64{1, -1, -1, 1}, 0},
+		{[]float64{1, 2, 3}, []float64{5, 5, 5}, 0},
+	}
+
+	for _, tt := range tests {
+		var sx, sy, sxx, syy, sxy float64
+		for i := range tt.x {
+			sx += tt.x[i]
+			sy += tt.y[i]
+			sxx += tt.x[i] * tt.x[i]
+			syy += tt.y[i] * tt.y[i]
+			sxy += tt.x[i] * tt.y[i]
+		}
+
+		if got := correlation(float64(len(tt.x)), sx, sy, sxx, syy, sxy); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("correlation(%v, %v)=%f, wanted %f", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestCorrelationStream(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	c := NewCorrelationStream(50, 40, 10, 5, 0.99999)
+
+	var r *ChangePoint
+	for i := 0; i < 5000 && r == nil; i++ {
+		x := rnd.NormFloat64()
+		y := x + 0.5*rnd.NormFloat64()
+
+		// after the change, y no longer tracks x, although neither moves
+		if i >= 3000 {
+			y = rnd.NormFloat64()
+		}
+
+		r = c.Push(x, y)
+	}
+
+	if r == nil || r.Offset != 60 {
+		t.Errorf("CorrelationStream returned %v, wanted change at group 60", r)
+	}
+}
@@ -0,0 +1,32 @@
+package change
+
+// counterRate returns the increase of a cumulative counter from prev to cur.
+// A decrease is treated as the counter wrapping at max if max is set, and
+// as a counter reset (restarting from zero) otherwise.
+func counterRate(prev, cur, max float64) float64 {
+	if cur >= prev {
+		return cur - prev
+	}
+
+	if max > 0 {
+		return (max - prev) + cur
+	}
+
+	return cur
+}
+
+// Rates converts a series of cumulative counter values to per-interval
+// increases, handling counter resets and, if max is non-zero, wraparound at
+// max.  The result has one fewer element than counters.
+func Rates(counters []float64, max float64) []float64 {
+	if len(counters) < 2 {
+		return nil
+	}
+
+	rates := make([]float64, len(counters)-1)
+	for i := range rates {
+		rates[i] = counterRate(counters[i], counters[i+1], max)
+	}
+
+	return rates
+}
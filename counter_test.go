@@ -0,0 +1,49 @@
+package change
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRates(t *testing.T) {
+
+	var tests = []struct {
+		counters []float64
+		max      float64
+		want     []float64
+	}{
+		{nil, 0, nil},
+		{[]float64{5}, 0, nil},
+		{[]float64{0, 2, 5, 9}, 0, []float64{2, 3, 4}},
+		{[]float64{10, 12, 3, 5}, 0, []float64{2, 3, 2}},     // reset
+		{[]float64{250, 254, 2, 5}, 256, []float64{4, 4, 3}}, // wraparound
+	}
+
+	for _, tt := range tests {
+		if got := Rates(tt.counters, tt.max); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Rates(%v, %f)=%v, wanted %v", tt.counters, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestStreamCounter(t *testing.T) {
+
+	s := NewStream(40, 5, 5, 0.95)
+	s.Counter = true
+
+	// the counter increases by 1 per item, then by 2 per item from item 60
+	var counter float64
+	var r *ChangePoint
+	for i := 0; i <= 100 && r == nil; i++ {
+		r = s.Push(counter)
+		if i < 60 {
+			counter++
+		} else {
+			counter += 2
+		}
+	}
+
+	if r == nil || r.Offset != 60 {
+		t.Errorf("Stream counter returned %v, wanted change at 60", r)
+	}
+}
@@ -0,0 +1,93 @@
+// Package datasets provides example series for trying out and testing the detectors
+/*
+Nile is the classic real-world change point dataset.  Synthetic returns
+generated series whose changes are known exactly, including one with no
+change, to check for false positives.  Every call returns a fresh copy, so
+the series may be modified.
+
+The well-log series of Ó Ruanaidh and Fitzgerald, the other standard
+example, is not included yet: no verified copy of its 4050 readings was
+available, and without one it cannot be shipped.  Until it is added, use
+Synthetic for series with many changes.
+*/
+package datasets
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Dataset is a series and the indexes of its known changes, each the index
+// of the first item after the change
+type Dataset struct {
+	Name    string
+	Values  []float64
+	Changes []int
+}
+
+//go:embed testdata/nile.txt
+var nile []byte
+
+// Nile returns the annual flow of the Nile at Aswan from 1871 to 1970, in
+// 10^8 cubic metres.  The flow dropped after the first Aswan dam was built,
+// from 1899.
+func Nile() Dataset {
+	return Dataset{Name: "nile", Values: parse(nile), Changes: []int{28}}
+}
+
+// parse reads one value per line, skipping blank lines and # comments
+func parse(data []byte) []float64 {
+	var values []float64
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		v, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			panic("datasets: invalid value " + line)
+		}
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// Synthetic returns generated series with unit-variance Gaussian noise.  The
+// values are the same on every call.
+func Synthetic() []Dataset {
+	rnd := rand.New(rand.NewSource(1))
+
+	// series returns n items of noise around the level returned by f
+	series := func(n int, f func(i int) float64) []float64 {
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = f(i) + rnd.NormFloat64()
+		}
+		return s
+	}
+
+	step := func(at int, delta float64) func(int) float64 {
+		return func(i int) float64 {
+			if i >= at {
+				return delta
+			}
+			return 0
+		}
+	}
+
+	return []Dataset{
+		{"none", series(500, func(int) float64 { return 0 }), nil},
+		{"step up", series(500, step(300, 2)), []int{300}},
+		{"step down", series(500, step(200, -2)), []int{200}},
+		{"small step", series(1000, step(500, 0.5)), []int{500}},
+		{"two steps", series(600, func(i int) float64 { return step(200, 3)(i) + step(400, -2)(i) }), []int{200, 400}},
+		{"transient", series(600, func(i int) float64 { return step(250, 3)(i) - step(350, 3)(i) }), []int{250, 350}},
+	}
+}
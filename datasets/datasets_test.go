@@ -0,0 +1,47 @@
+package datasets
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNile(t *testing.T) {
+
+	d := Nile()
+
+	if len(d.Values) != 100 {
+		t.Fatalf("Nile has %d values, wanted 100", len(d.Values))
+	}
+
+	var sum float64
+	for _, v := range d.Values {
+		sum += v
+	}
+	if mean := sum / 100; math.Abs(mean-919.35) > 1e-9 {
+		t.Errorf("Nile mean=%v, wanted 919.35", mean)
+	}
+
+	// a fresh copy on each call
+	d.Values[0] = 0
+	if Nile().Values[0] != 1120 {
+		t.Errorf("Nile shares its values between calls")
+	}
+}
+
+func TestSynthetic(t *testing.T) {
+
+	a, b := Synthetic(), Synthetic()
+
+	for i, d := range a {
+		for _, c := range d.Changes {
+			if c <= 0 || c >= len(d.Values) {
+				t.Errorf("%s: change %d out of range", d.Name, c)
+			}
+		}
+		for j := range d.Values {
+			if d.Values[j] != b[i].Values[j] {
+				t.Fatalf("%s: values differ between calls", d.Name)
+			}
+		}
+	}
+}
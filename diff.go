@@ -0,0 +1,69 @@
+package change
+
+import "sort"
+
+// Move is a change point found by both runs compared by Diff, at different offsets
+type Move struct {
+	From, To ChangePoint
+}
+
+// RunDiff is the difference between the change points found by two runs
+type RunDiff struct {
+	// Added are the change points found only by the second run
+	Added []ChangePoint
+
+	// Removed are the change points found only by the first run
+	Removed []ChangePoint
+
+	// Moved are the change points found by both runs within tolerance of
+	// each other but not at the same offset
+	Moved []Move
+
+	// Unchanged is the number of change points found at the same offset by both runs
+	Unchanged int
+}
+
+// Same reports whether both runs found the same change points
+func (d *RunDiff) Same() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0
+}
+
+// Diff compares the change points found in the same series by two runs, such
+// as with different parameters or before and after upgrading, so that a
+// change in configuration can be checked before it is rolled out.  Change
+// points whose offsets are within tolerance are matched in order.
+func Diff(before, after []ChangePoint, tolerance int) RunDiff {
+	byOffset := func(changes []ChangePoint) []ChangePoint {
+		c := append([]ChangePoint(nil), changes...)
+		sort.SliceStable(c, func(i, j int) bool { return c[i].Offset < c[j].Offset })
+		return c
+	}
+
+	a, b := byOffset(before), byOffset(after)
+
+	var d RunDiff
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch delta := b[j].Offset - a[i].Offset; {
+		case delta == 0:
+			d.Unchanged++
+		case abs(delta) <= tolerance:
+			d.Moved = append(d.Moved, Move{a[i], b[j]})
+		case delta > 0:
+			d.Removed = append(d.Removed, a[i])
+			i++
+			continue
+		default:
+			d.Added = append(d.Added, b[j])
+			j++
+			continue
+		}
+		i++
+		j++
+	}
+
+	d.Removed = append(d.Removed, a[i:]...)
+	d.Added = append(d.Added, b[j:]...)
+
+	return d
+}
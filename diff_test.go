@@ -0,0 +1,58 @@
+package change
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+
+	points := func(offsets ...int) []ChangePoint {
+		var c []ChangePoint
+		for _, o := range offsets {
+			c = append(c, ChangePoint{Offset: o})
+		}
+		return c
+	}
+
+	offsets := func(changes []ChangePoint) []int {
+		var o []int
+		for _, c := range changes {
+			o = append(o, c.Offset)
+		}
+		return o
+	}
+
+	var tests = []struct {
+		before, after []int
+		added         []int
+		removed       []int
+		moved         int
+		unchanged     int
+	}{
+		{nil, nil, nil, nil, 0, 0},
+		{[]int{10, 50}, []int{10, 50}, nil, nil, 0, 2},
+		{[]int{10, 50}, []int{12, 50}, nil, nil, 1, 1},
+		{[]int{10}, []int{10, 90}, []int{90}, nil, 0, 1},
+		{[]int{10, 90}, []int{10}, nil, []int{90}, 0, 1},
+		{[]int{50, 10}, []int{30}, []int{30}, []int{10, 50}, 0, 0},
+		{[]int{10, 20}, []int{14}, nil, []int{20}, 1, 0},
+	}
+
+	for _, tt := range tests {
+		d := Diff(points(tt.before...), points(tt.after...), 5)
+
+		if got := offsets(d.Added); !slices.Equal(got, tt.added) {
+			t.Errorf("Diff(%v, %v).Added=%v, wanted %v", tt.before, tt.after, got, tt.added)
+		}
+		if got := offsets(d.Removed); !slices.Equal(got, tt.removed) {
+			t.Errorf("Diff(%v, %v).Removed=%v, wanted %v", tt.before, tt.after, got, tt.removed)
+		}
+		if len(d.Moved) != tt.moved || d.Unchanged != tt.unchanged {
+			t.Errorf("Diff(%v, %v) moved=%d unchanged=%d, wanted %d and %d", tt.before, tt.after, len(d.Moved), d.Unchanged, tt.moved, tt.unchanged)
+		}
+		if same := tt.added == nil && tt.removed == nil && tt.moved == 0; d.Same() != same {
+			t.Errorf("Diff(%v, %v).Same()=%v, wanted %v", tt.before, tt.after, d.Same(), same)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package change
+
+import (
+	"math"
+
+	"github.com/dgryski/go-change/trend"
+)
+
+// Drift is a gradual trend found by DriftDetector.Check
+type Drift struct {
+	// Start is the estimated index in the window where the drift began
+	Start int
+
+	// Slope is the estimated change per item after Start
+	Slope float64
+
+	// Tau is Kendall's tau for the data after Start
+	Tau float64
+
+	// Confidence is the confidence returned by a Mann-Kendall trend test
+	Confidence float64
+}
+
+// DriftDetector detects slow monotonic drifts, such as memory leaks, which never
+// produce the sharp step that Detector looks for.
+type DriftDetector struct {
+	MinSampleSize int
+	MinConfidence float64
+}
+
+// Check returns the drift in the window, if any.
+//
+// The start of the drift is estimated by fitting a "hinge" model, flat up to
+// the start and linear afterwards, at every candidate position and keeping
+// the best fit by least squares.  The data after the start is then tested for
+// a monotonic trend with the Mann-Kendall test.
+func (d *DriftDetector) Check(window []float64) *Drift {
+
+	n := len(window)
+
+	minSampleSize := d.MinSampleSize
+	if minSampleSize <= 0 {
+		minSampleSize = DefaultMinSampleSize
+	}
+
+	if n < minSampleSize {
+		return nil
+	}
+
+	bestRSS := math.Inf(1)
+	var best Drift
+
+	for l := 0; l <= n-minSampleSize; l++ {
+		slope, rss := hingeFit(window, l)
+		if rss < bestRSS {
+			bestRSS = rss
+			best.Start, best.Slope = l, slope
+		}
+	}
+
+	tau, p := trend.MannKendall(window[best.Start:])
+	best.Tau = tau
+	best.Confidence = 1 - p
+
+	if best.Confidence <= d.MinConfidence {
+		return nil
+	}
+
+	return &best
+}
+
+// hingeFit fits y = a + b*max(0, i-l) by least squares and returns b and the residual sum of squares
+func hingeFit(ys []float64, l int) (float64, float64) {
+	var sx, sy, sxx, sxy, syy float64
+	for i, y := range ys {
+		x := float64(i - l)
+		if x < 0 {
+			x = 0
+		}
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+		syy += y * y
+	}
+
+	n := float64(len(ys))
+	vx := sxx - sx*sx/n
+	cxy := sxy - sx*sy/n
+	vy := syy - sy*sy/n
+
+	if vx == 0 {
+		return 0, vy
+	}
+
+	b := cxy / vx
+	return b, vy - b*cxy
+}
@@ -0,0 +1,49 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDriftDetector(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	var w []float64
+	for i := 0; i < 200; i++ {
+		v := 10 + rnd.Float64()
+		if i >= 80 {
+			v += 0.05 * float64(i-80)
+		}
+		w = append(w, v)
+	}
+
+	d := DriftDetector{MinSampleSize: 20, MinConfidence: 0.99}
+
+	r := d.Check(w)
+	if r == nil {
+		t.Fatalf("DriftDetector failed to find drift")
+	}
+
+	if r.Start < 70 || r.Start > 90 {
+		t.Errorf("drift start=%d, wanted about 80", r.Start)
+	}
+
+	if math.Abs(r.Slope-0.05) > 0.01 {
+		t.Errorf("drift slope=%f, wanted about 0.05", r.Slope)
+	}
+
+	if r.Tau <= 0 {
+		t.Errorf("drift tau=%f, wanted positive", r.Tau)
+	}
+
+	var flat []float64
+	for i := 0; i < 200; i++ {
+		flat = append(flat, 10+rnd.Float64())
+	}
+
+	if r := d.Check(flat); r != nil {
+		t.Errorf("DriftDetector found drift %+v in flat data", r)
+	}
+}
@@ -0,0 +1,70 @@
+package change
+
+import "time"
+
+// Event is a known event, such as a deploy or a configuration push, which may
+// explain a change.  Set Time to match events against change points found by
+// a TimeStream, or Offset to match them by index.
+type Event struct {
+	Name   string
+	Time   time.Time
+	Offset int
+}
+
+// Attribution is a change point and the known event nearest to it
+type Attribution struct {
+	ChangePoint
+
+	// Event is the nearest known event within the tolerance, or nil if the
+	// change is unexplained
+	Event *Event
+}
+
+// AttributeTimes matches each change point with the known event closest to it
+// in time, if one is within tolerance.
+func AttributeTimes(changes []ChangePoint, events []Event, tolerance time.Duration) []Attribution {
+	return attribute(changes, events, func(cp *ChangePoint, e *Event) float64 {
+		return float64(absDuration(cp.Time.Sub(e.Time)))
+	}, float64(tolerance))
+}
+
+// AttributeOffsets matches each change point with the known event closest to
+// it by offset, if one is within tolerance.
+func AttributeOffsets(changes []ChangePoint, events []Event, tolerance int) []Attribution {
+	return attribute(changes, events, func(cp *ChangePoint, e *Event) float64 {
+		return float64(abs(cp.Offset - e.Offset))
+	}, float64(tolerance))
+}
+
+func attribute(changes []ChangePoint, events []Event, dist func(*ChangePoint, *Event) float64, tolerance float64) []Attribution {
+
+	r := make([]Attribution, len(changes))
+
+	for i := range changes {
+		r[i].ChangePoint = changes[i]
+
+		best := tolerance
+		for j := range events {
+			if d := dist(&changes[i], &events[j]); d <= best {
+				best = d
+				r[i].Event = &events[j]
+			}
+		}
+	}
+
+	return r
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
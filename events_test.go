@@ -0,0 +1,43 @@
+package change
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAttributeTimes(t *testing.T) {
+
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Name: "deploy v1", Time: t0},
+		{Name: "config push", Time: t0.Add(3 * time.Minute)},
+	}
+
+	changes := []ChangePoint{
+		{Time: t0.Add(2 * time.Minute)},
+		{Time: t0.Add(time.Hour)},
+	}
+
+	r := AttributeTimes(changes, events, 5*time.Minute)
+
+	if r[0].Event == nil || r[0].Event.Name != "config push" {
+		t.Errorf("first change attributed to %v, wanted config push", r[0].Event)
+	}
+
+	if r[1].Event != nil {
+		t.Errorf("second change attributed to %v, wanted none", r[1].Event)
+	}
+}
+
+func TestAttributeOffsets(t *testing.T) {
+
+	events := []Event{{Name: "deploy", Offset: 95}}
+	changes := []ChangePoint{{Offset: 100}, {Offset: 200}}
+
+	r := AttributeOffsets(changes, events, 10)
+
+	if r[0].Event == nil || r[1].Event != nil {
+		t.Errorf("AttributeOffsets=%+v, wanted only the first change attributed", r)
+	}
+}
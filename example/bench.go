@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/offline"
+)
+
+// benchRun is the ns/op results of every benchmark in one file of go test
+// -bench output
+type benchRun struct {
+	commit  string
+	results map[string][]float64
+}
+
+// bench implements the bench subcommand.  Each file is the benchmark output
+// for one commit, in commit order; the commit is taken from a "commit:"
+// configuration line, or the file name if there is none.  Commits where the
+// median ns/op of a benchmark changed are reported, and the exit status is
+// exitChange if there are any.
+func bench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	pattern := fs.String("bench", ".", "only analyse benchmarks matching this regexp")
+	minSample := fs.Int("ms", 5, "min number of commits either side of a change")
+	confidence := fs.Float64("confidence", 0.995, "min confidence")
+	quiet := fs.Bool("quiet", false, "write no output; only set the exit status")
+	fs.Parse(args)
+
+	re, err := regexp.Compile(*pattern)
+	if err != nil {
+		fatal(err)
+	}
+
+	var runs []benchRun
+	names := make(map[string]bool)
+	for _, fname := range fs.Args() {
+		r, err := readBench(fname)
+		if err != nil {
+			fatal(err)
+		}
+		for name := range r.results {
+			if re.MatchString(name) {
+				names[name] = true
+			}
+		}
+		runs = append(runs, r)
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	d := &change.Detector{MinSampleSize: *minSample, MinConfidence: *confidence}
+
+	status := exitNoChange
+	for _, name := range sorted {
+		// commits holds the commit of each item of the series, skipping
+		// commits where the benchmark was not run
+		var series []float64
+		var commits []string
+		for _, r := range runs {
+			if v, ok := r.results[name]; ok {
+				series = append(series, median(v))
+				commits = append(commits, r.commit)
+			}
+		}
+
+		for cp := range offline.Changes(series, d) {
+			status = exitChange
+			if *quiet {
+				continue
+			}
+			fmt.Printf("%s: ns/op changed at commit %s: %.4g -> %.4g (%+.1f%%, confidence %.4f)\n",
+				name, commits[cp.Index], cp.Before.Mean(), cp.After.Mean(), 100*cp.Difference/cp.Before.Mean(), cp.Confidence)
+		}
+	}
+
+	os.Exit(status)
+}
+
+// readBench reads the ns/op results from a file of go test -bench output
+func readBench(fname string) (benchRun, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return benchRun{}, fmt.Errorf("open failed: %v", err)
+	}
+	defer f.Close()
+
+	r := benchRun{
+		commit:  strings.TrimSuffix(filepath.Base(fname), filepath.Ext(fname)),
+		results: make(map[string][]float64),
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if v, ok := strings.CutPrefix(line, "commit:"); ok {
+			r.commit = strings.TrimSpace(v)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+
+		// name, iterations, then pairs of value and unit
+		for i := 2; i+1 < len(fields); i += 2 {
+			if fields[i+1] != "ns/op" {
+				continue
+			}
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return benchRun{}, fmt.Errorf("%s: error parsing <%s>: %v", fname, line, err)
+			}
+			r.results[fields[0]] = append(r.results[fields[0]], v)
+		}
+	}
+
+	return r, scanner.Err()
+}
+
+// median returns the median of xs, reordering it
+func median(xs []float64) float64 {
+	sort.Float64s(xs)
+	n := len(xs)
+	if n%2 == 1 {
+		return xs[n/2]
+	}
+	return (xs[n/2-1] + xs[n/2]) / 2
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dgryski/go-change"
+)
+
+// canary implements the canary subcommand, which compares a canary series
+// with its baseline and exits with exitChange if the canary has changed
+// relative to the baseline
+func canary(args []string) {
+	fs := flag.NewFlagSet("canary", flag.ExitOnError)
+	baselineFile := fs.String("baseline", "", "baseline series file")
+	canaryFile := fs.String("canary", "", "canary series file")
+	minSample := fs.Int("ms", 30, "min sample size")
+	confidence := fs.Float64("confidence", 0.995, "min confidence")
+	format := fs.String("input-format", formatAuto, "input format: auto, lines, csv, json, ndjson or graphite")
+	var nf numberFormat
+	nf.flags(fs)
+	quiet := fs.Bool("quiet", false, "write no output; only set the exit status")
+	fs.Parse(args)
+
+	if *baselineFile == "" || *canaryFile == "" {
+		fatal(fmt.Errorf("canary: -baseline and -canary are required"))
+	}
+
+	read := func(fname string) []float64 {
+		f, err := os.Open(fname)
+		if err != nil {
+			fatal(fmt.Errorf("open failed: %v", err))
+		}
+		defer f.Close()
+
+		in, err := readSeries(f, *format, &nf)
+		if err != nil {
+			fatal(fmt.Errorf("%s: error reading input: %v", fname, err))
+		}
+		return in.values
+	}
+
+	d := &change.Detector{MinSampleSize: *minSample, MinConfidence: *confidence}
+	r := d.CheckCanary(read(*baselineFile), read(*canaryFile))
+
+	if !*quiet {
+		describe := func(name string, cp *change.ChangePoint) {
+			if cp == nil {
+				fmt.Printf("%s: no change\n", name)
+				return
+			}
+			fmt.Printf("%s: change at offset=%d of %f (confidence %f)\n", name, cp.Offset, cp.Difference, cp.Confidence)
+		}
+		describe("baseline", r.Baseline)
+		describe("canary", r.Canary)
+		describe("canary relative to baseline", r.Change)
+	}
+
+	if r.Change != nil {
+		os.Exit(exitChange)
+	}
+	os.Exit(exitNoChange)
+}
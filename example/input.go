@@ -0,0 +1,433 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// input formats accepted by readSeries
+const (
+	formatAuto     = "auto"
+	formatLines    = "lines"
+	formatCSV      = "csv"
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatGraphite = "graphite"
+)
+
+// input is a series read by readSeries
+type input struct {
+	values []float64
+
+	// times are the timestamps of the values, or nil if the input has none
+	times []time.Time
+}
+
+// add appends a value, and its timestamp if the input has them
+func (in *input) add(v float64, t time.Time, timed bool) {
+	in.values = append(in.values, v)
+	if timed {
+		in.times = append(in.times, t)
+	}
+}
+
+// between returns the part of the input with timestamps in [from, to).  Zero
+// times are unbounded.
+func (in *input) between(from, to time.Time) input {
+	var r input
+	for i, t := range in.times {
+		if (!from.IsZero() && t.Before(from)) || (!to.IsZero() && !t.Before(to)) {
+			continue
+		}
+		r.add(in.values[i], t, true)
+	}
+	return r
+}
+
+// parseTime parses an RFC3339 time or a unix timestamp in seconds, or in
+// milliseconds if it is too large to be in seconds
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: wanted RFC3339 or a unix timestamp", s)
+	}
+
+	if f > 1e11 {
+		return time.UnixMilli(int64(f)), nil
+	}
+
+	// f*1e9 nanoseconds would overflow an int64 after 2262
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))), nil
+}
+
+// numberFormat controls how values are parsed
+type numberFormat struct {
+	// unit is what durations such as 12ms or 1.5s are converted to a number of
+	unit time.Duration
+
+	// decimalComma is set if ',' is the decimal separator and '.' separates
+	// thousands, rather than the other way around
+	decimalComma bool
+
+	// null is the token for a missing value; missing values are skipped
+	null string
+}
+
+// flags registers flags setting the number format on fs
+func (nf *numberFormat) flags(fs *flag.FlagSet) {
+	fs.DurationVar(&nf.unit, "duration-unit", time.Millisecond, "unit that duration values such as 12ms or 1.5s are converted to")
+	fs.BoolVar(&nf.decimalComma, "decimal-comma", false, "numbers use ',' as the decimal separator and '.' for thousands")
+	fs.StringVar(&nf.null, "null", "null", "token for a missing value")
+}
+
+// errMissing is returned by parseValue for the null token
+var errMissing = errors.New("missing value")
+
+// digitSpacer removes the characters used to group digits, other than the
+// thousands separator
+var digitSpacer = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "_", "", "'", "")
+
+// parseValue parses a float, tolerating thousands separators and surrounding
+// whitespace, or a duration which is converted to a number of units
+func (nf *numberFormat) parseValue(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == nf.null {
+		return 0, errMissing
+	}
+
+	num := digitSpacer.Replace(s)
+	if nf.decimalComma {
+		num = strings.ReplaceAll(num, ".", "")
+		num = strings.ReplaceAll(num, ",", ".")
+	} else {
+		num = strings.ReplaceAll(num, ",", "")
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err == nil {
+		return f, nil
+	}
+
+	if nf.decimalComma {
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+	d, derr := time.ParseDuration(s)
+	if derr != nil {
+		return 0, err
+	}
+	return float64(d) / float64(nf.unit), nil
+}
+
+// readSeries reads the values of a series from r in the given format,
+// sniffing the format from the start of the input if it is formatAuto.
+// Values in line and CSV input are parsed according to nf.
+func readSeries(r io.Reader, format string, nf *numberFormat) (input, error) {
+	br, err := decompress(bufio.NewReader(r))
+	if err != nil {
+		return input{}, err
+	}
+
+	if format == formatAuto {
+		if format, err = sniff(br, nf); err != nil {
+			return input{}, err
+		}
+	}
+
+	switch format {
+	case formatLines:
+		return readLines(br, nf)
+	case formatCSV:
+		return readCSV(br, nf)
+	case formatJSON:
+		return readJSON(br)
+	case formatNDJSON:
+		return readNDJSON(br)
+	case formatGraphite:
+		return readGraphite(br)
+	}
+
+	return input{}, fmt.Errorf("unknown input format %q", format)
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// decompress returns a reader for the decompressed input if br is gzip
+// compressed, and br otherwise.  zstd compressed input is recognised and
+// rejected with an error, since the standard library has no zstd decoder.
+func decompress(br *bufio.Reader) (*bufio.Reader, error) {
+	magic, _ := br.Peek(len(zstdMagic))
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return bufio.NewReader(zr), nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		// the standard library has no zstd decoder
+		return nil, errors.New("zstd compressed input is not supported, only gzip: decompress it with zstd -d")
+	}
+
+	return br, nil
+}
+
+// thousands matches a number whose integer part is grouped in threes by
+// commas, such as 1,234 or -12,345,678.9
+var thousands = regexp.MustCompile(`^[+-]?[0-9]{1,3}(,[0-9]{3})+(\.[0-9]*)?$`)
+
+// sniff guesses the format of the input from its first bytes.  A decimal
+// comma, or a first line which is a single number with thousands separators,
+// does not indicate CSV.
+func sniff(br *bufio.Reader, nf *numberFormat) (string, error) {
+	// Peek returns ErrBufferFull for input longer than the buffer, but
+	// still returns the whole buffer
+	buf, err := br.Peek(br.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", err
+	}
+
+	buf = bytes.TrimLeft(buf, " \t\r\n")
+	if len(buf) == 0 {
+		return formatLines, nil
+	}
+
+	switch buf[0] {
+	case '{':
+		return formatNDJSON, nil
+	case '[':
+		// Graphite's render API returns an array of objects
+		if rest := bytes.TrimLeft(buf[1:], " \t\r\n"); len(rest) > 0 && rest[0] == '{' {
+			return formatGraphite, nil
+		}
+		return formatJSON, nil
+	}
+
+	// a line such as 1,234 is a single value with a thousands separator
+	line := firstLine(buf)
+	if !nf.decimalComma && thousands.Match(bytes.TrimSpace(line)) {
+		return formatLines, nil
+	}
+
+	separators := ",;"
+	if nf.decimalComma {
+		separators = ";"
+	}
+	if bytes.ContainsAny(line, separators) {
+		return formatCSV, nil
+	}
+
+	return formatLines, nil
+}
+
+// firstLine returns buf up to its first newline
+func firstLine(buf []byte) []byte {
+	if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+		return buf[:i]
+	}
+	return buf
+}
+
+// readLines reads one value per line, skipping lines which don't parse
+func readLines(r io.Reader, nf *numberFormat) (input, error) {
+	var in input
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		item, err := nf.parseValue(text)
+		if err == errMissing {
+			continue
+		}
+		if err != nil {
+			log.Printf("error parsing <%s>: %s\n", text, err)
+			continue
+		}
+		in.add(item, time.Time{}, false)
+	}
+
+	return in, scanner.Err()
+}
+
+// readCSV reads the column named "value" of a CSV file, or its last column if
+// there is no such header.  A first row which doesn't parse is a header.
+// Timestamps are read from a column named "time" or "timestamp", or from the
+// first column of a file without a header if it holds times.  Fields are
+// separated by semicolons if the first line has a semicolon, as in exports
+// that use decimal commas.
+func readCSV(br *bufio.Reader, nf *numberFormat) (input, error) {
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+
+	if first, _ := br.Peek(br.Size()); bytes.ContainsRune(firstLine(first), ';') {
+		cr.Comma = ';'
+	}
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return input{}, err
+	}
+	if len(records) == 0 {
+		return input{}, nil
+	}
+
+	col, timeCol := len(records[0])-1, -1
+	if _, err := nf.parseValue(records[0][col]); err != nil && err != errMissing {
+		for i, name := range records[0] {
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "value":
+				col = i
+			case "time", "timestamp":
+				timeCol = i
+			}
+		}
+		records = records[1:]
+	} else if col > 0 {
+		if _, err := parseTime(records[0][0]); err == nil {
+			timeCol = 0
+		}
+	}
+
+	var in input
+	for i, rec := range records {
+		if col >= len(rec) || timeCol >= len(rec) {
+			return input{}, fmt.Errorf("csv line %d: missing column", i+1)
+		}
+		item, err := nf.parseValue(rec[col])
+		if err == errMissing {
+			continue
+		}
+		if err != nil {
+			log.Printf("error parsing <%s>: %s\n", rec[col], err)
+			continue
+		}
+
+		var t time.Time
+		if timeCol >= 0 {
+			if t, err = parseTime(rec[timeCol]); err != nil {
+				log.Printf("error parsing <%s>: %s\n", rec[timeCol], err)
+				continue
+			}
+		}
+		in.add(item, t, timeCol >= 0)
+	}
+
+	return in, nil
+}
+
+// readJSON reads a JSON array of numbers
+func readJSON(r io.Reader) (input, error) {
+	var values []float64
+	if err := json.NewDecoder(r).Decode(&values); err != nil {
+		return input{}, err
+	}
+	return input{values: values}, nil
+}
+
+// readNDJSON reads one JSON value per line, either a number or an object
+// with a numeric "value" field.  Objects may also have a "time" or
+// "timestamp" field holding an RFC3339 string or a unix timestamp; if the
+// first value has one, values without one are skipped.
+func readNDJSON(r io.Reader) (input, error) {
+	var in input
+	var timed, first = false, true
+
+	dec := json.NewDecoder(r)
+	for {
+		var v json.RawMessage
+		if err := dec.Decode(&v); err == io.EOF {
+			return in, nil
+		} else if err != nil {
+			return input{}, err
+		}
+
+		var item float64
+		var t time.Time
+		var hasTime bool
+		if err := json.Unmarshal(v, &item); err != nil {
+			var obj struct {
+				Value     *float64        `json:"value"`
+				Time      json.RawMessage `json:"time"`
+				Timestamp json.RawMessage `json:"timestamp"`
+			}
+			if err := json.Unmarshal(v, &obj); err != nil || obj.Value == nil {
+				log.Printf("error parsing <%s>: no value\n", v)
+				continue
+			}
+			item = *obj.Value
+
+			ts := obj.Time
+			if ts == nil {
+				ts = obj.Timestamp
+			}
+			if ts != nil {
+				if t, err = parseTime(strings.Trim(string(ts), `"`)); err != nil {
+					log.Printf("error parsing <%s>: %s\n", v, err)
+					continue
+				}
+				hasTime = true
+			}
+		}
+
+		if first {
+			timed, first = hasTime, false
+		}
+		if timed && !hasTime {
+			log.Printf("error parsing <%s>: no time\n", v)
+			continue
+		}
+		in.add(item, t, timed)
+	}
+}
+
+// readGraphite reads the datapoints of the first target in the output of
+// Graphite's render API, skipping null values
+func readGraphite(r io.Reader) (input, error) {
+	var targets []struct {
+		Target     string        `json:"target"`
+		Datapoints [][2]*float64 `json:"datapoints"`
+	}
+	if err := json.NewDecoder(r).Decode(&targets); err != nil {
+		return input{}, err
+	}
+	if len(targets) == 0 {
+		return input{}, nil
+	}
+	if len(targets) > 1 {
+		log.Printf("using the first of %d graphite targets: %s\n", len(targets), targets[0].Target)
+	}
+
+	var in input
+	for _, dp := range targets[0].Datapoints {
+		if dp[0] != nil && dp[1] != nil {
+			in.add(*dp[0], time.Unix(int64(*dp[1]), 0), true)
+		}
+	}
+
+	return in, nil
+}
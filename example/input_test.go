@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSeries(t *testing.T) {
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte("4\n5\n6\n"))
+	zw.Close()
+
+	t0 := time.Unix(1700000000, 0)
+
+	var tests = []struct {
+		name   string
+		input  string
+		format string
+		nf     numberFormat
+		want   []float64
+		times  []time.Time
+	}{
+		{"lines", "1\n2\n\n3\n", formatAuto, numberFormat{}, []float64{1, 2, 3}, nil},
+		{"lines skip", "1\nfoo\nnull\n3\n", formatAuto, numberFormat{}, []float64{1, 3}, nil},
+		{"durations", "12ms\n1.5s\n", formatAuto, numberFormat{}, []float64{12, 1500}, nil},
+		{"thousands", "1,234\n5,678\n9,012\n", formatAuto, numberFormat{}, []float64{1234, 5678, 9012}, nil},
+		{"decimal comma", "1,5\n2,25\n", formatAuto, numberFormat{decimalComma: true}, []float64{1.5, 2.25}, nil},
+		{"csv", "a,2\nb,4\n", formatAuto, numberFormat{}, []float64{2, 4}, nil},
+		{"csv header", "name,value,other\na,1,9\nb,2,9\n", formatAuto, numberFormat{}, []float64{1, 2}, nil},
+		{"csv semicolon", "time;value\n1700000000;1,5\n1700000060;2,5\n", formatAuto, numberFormat{decimalComma: true}, []float64{1.5, 2.5}, []time.Time{t0, t0.Add(time.Minute)}},
+		{"csv time header", "timestamp,value\n1700000000,1\n1700000060,2\n", formatAuto, numberFormat{}, []float64{1, 2}, []time.Time{t0, t0.Add(time.Minute)}},
+		{"csv time column", "2023-11-14T22:13:20Z,1\n2023-11-14T22:14:20Z,2\n", formatAuto, numberFormat{}, []float64{1, 2}, []time.Time{t0, t0.Add(time.Minute)}},
+		{"csv forced", "1\n2\n", formatCSV, numberFormat{}, []float64{1, 2}, nil},
+		{"json", " [1, 2.5, 3]", formatAuto, numberFormat{}, []float64{1, 2.5, 3}, nil},
+		{"ndjson", "1\n2\n", formatNDJSON, numberFormat{}, []float64{1, 2}, nil},
+		{"ndjson objects", `{"value": 1, "time": 1700000000}` + "\n" + `{"value": 2}` + "\n" + `{"value": 3, "timestamp": "2023-11-14T22:14:20Z"}`, formatAuto, numberFormat{}, []float64{1, 3}, []time.Time{t0, t0.Add(time.Minute)}},
+		{"graphite", `[{"target": "a", "datapoints": [[1, 1700000000], [null, 1700000030], [2, 1700000060]]}, {"target": "b", "datapoints": [[9, 1700000000]]}]`, formatAuto, numberFormat{}, []float64{1, 2}, []time.Time{t0, t0.Add(time.Minute)}},
+		{"gzip", gz.String(), formatAuto, numberFormat{}, []float64{4, 5, 6}, nil},
+		{"empty", "", formatAuto, numberFormat{}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		tt.nf.unit, tt.nf.null = time.Millisecond, "null"
+		in, err := readSeries(strings.NewReader(tt.input), tt.format, &tt.nf)
+		if err != nil || !slices.Equal(in.values, tt.want) {
+			t.Errorf("%s: readSeries=%v, %v, wanted %v", tt.name, in.values, err, tt.want)
+		}
+		if !slices.EqualFunc(in.times, tt.times, time.Time.Equal) {
+			t.Errorf("%s: readSeries times=%v, wanted %v", tt.name, in.times, tt.times)
+		}
+	}
+
+	nf := numberFormat{unit: time.Millisecond, null: "null"}
+	for _, input := range []string{"\x28\xb5\x2f\xfd", "\x1f\x8b"} {
+		if _, err := readSeries(strings.NewReader(input), formatAuto, &nf); err == nil {
+			t.Errorf("readSeries(%q) succeeded, wanted an error", input)
+		}
+	}
+	if _, err := readSeries(strings.NewReader("1\n"), "xml", &nf); err == nil {
+		t.Errorf("readSeries with an unknown format succeeded, wanted an error")
+	}
+}
+
+func TestParseValue(t *testing.T) {
+
+	var tests = []struct {
+		s            string
+		decimalComma bool
+		want         float64
+		wantErr      bool
+	}{
+		{" 3.5 ", false, 3.5, false},
+		{"1,234.5", false, 1234.5, false},
+		{"1 234.5", false, 1234.5, false},
+		{"1_000", false, 1000, false},
+		{"1.234,5", true, 1234.5, false},
+		{"250ms", false, 250, false},
+		{"1,5s", true, 1500, false},
+		{"null", false, 0, true},
+		{"foo", false, 0, true},
+	}
+
+	for _, tt := range tests {
+		nf := numberFormat{unit: time.Millisecond, decimalComma: tt.decimalComma, null: "null"}
+		got, err := nf.parseValue(tt.s)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseValue(%q, decimalComma=%v)=%v, %v, wanted %v", tt.s, tt.decimalComma, got, err, tt.want)
+		}
+	}
+
+	nf := numberFormat{unit: time.Millisecond, null: "NA"}
+	if _, err := nf.parseValue("NA"); err != errMissing {
+		t.Errorf("parseValue(NA)=%v, wanted errMissing", err)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+
+	var tests = []struct {
+		s    string
+		want time.Time
+	}{
+		{"2023-11-14T22:13:20Z", time.Unix(1700000000, 0)},
+		{"1700000000", time.Unix(1700000000, 0)},
+		{"1700000000.25", time.Unix(1700000000, 250000000)},
+		{"1700000000123", time.UnixMilli(1700000000123)},
+		{"10000000000", time.Unix(10000000000, 0)},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTime(tt.s)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseTime(%q)=%v, %v, wanted %v", tt.s, got, err, tt.want)
+		}
+	}
+
+	if _, err := parseTime("yesterday"); err == nil {
+		t.Errorf("parseTime(yesterday) succeeded, wanted an error")
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"html/template"
@@ -9,118 +8,267 @@ import (
 	"log"
 	"math"
 	"os"
+	"path/filepath"
 	"sort"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/report"
 )
 
+// fileList is a flag that may be given more than once
+type fileList []string
+
+func (f *fileList) String() string     { return strings.Join(*f, ",") }
+func (f *fileList) Set(s string) error { *f = append(*f, s); return nil }
+
+// exit statuses, so the tool can be used as a gate in scripts
+const (
+	exitNoChange = 0
+	exitChange   = 1
+	exitError    = 2
+)
+
+// fatal reports err and exits with exitError
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(exitError)
+}
+
+type options struct {
+	windowSize     int
+	minSample      int
+	blockSize      int
+	compressPoints int
+	counter        bool
+	format         string
+	numberFormat   numberFormat
+	from, to       time.Time
+}
+
 func main() {
-	windowSize := flag.Int("w", 120, "window size")
-	minSample := flag.Int("ms", 30, "min sample size")
-	blockSize := flag.Int("bs", 10, "block size")
-	compressPoints := flag.Int("cp", 10, "compress points for graph display")
-	fname := flag.String("f", "", "file name")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "canary":
+			canary(os.Args[2:])
+		case "bench":
+			bench(os.Args[2:])
+		case "scores":
+			scores(os.Args[2:])
+		}
+	}
+
+	var opts options
+	var fnames fileList
+
+	flag.IntVar(&opts.windowSize, "w", 120, "window size")
+	flag.IntVar(&opts.minSample, "ms", 30, "min sample size, at least 2 and at most half the window size")
+	flag.IntVar(&opts.blockSize, "bs", 10, "block size")
+	flag.IntVar(&opts.compressPoints, "cp", 10, "compress points for graph display")
+	flag.Var(&fnames, "f", "file name (may be repeated to compare several series)")
 	ymin := flag.Int("ymin", 0, "minimum y value for graph")
+	flag.BoolVar(&opts.counter, "counter", false, "input is a cumulative counter; detect changes in its rate")
+	tolerance := flag.Int("tol", 10, "index tolerance for matching change points across series")
+	flag.StringVar(&opts.format, "input-format", formatAuto, "input format: auto, lines, csv, json, ndjson or graphite")
+	opts.numberFormat.flags(flag.CommandLine)
+	tmplFile := flag.String("template", "", "template file redefining blocks of the report")
+	from := flag.String("from", "", "only analyse items at or after this time (RFC3339 or unix)")
+	to := flag.String("to", "", "only analyse items before this time (RFC3339 or unix)")
+	plot := flag.String("plot", "html", "output: an html report, or term for a sparkline in the terminal")
+	quiet := flag.Bool("quiet", false, "write no report or log output; only set the exit status (0 no change, 1 change found, 2 error)")
 
 	flag.Parse()
 
-	var f io.Reader
+	if *plot != "html" && *plot != "term" {
+		fmt.Fprintf(os.Stderr, "unknown -plot %q: wanted html or term\n", *plot)
+		flag.Usage()
+		os.Exit(exitError)
+	}
 
-	if *fname == "" {
-		log.Println("reading from stdin")
-		f = os.Stdin
-	} else {
+	if *quiet {
+		log.SetOutput(io.Discard)
+	}
+
+	for _, f := range []struct {
+		s string
+		t *time.Time
+	}{{*from, &opts.from}, {*to, &opts.to}} {
+		if f.s == "" {
+			continue
+		}
+		t, err := parseTime(f.s)
+		if err != nil {
+			fatal(err)
+		}
+		*f.t = t
+	}
+
+	var tmpl *template.Template
+	if *tmplFile != "" {
 		var err error
-		f, err = os.Open(*fname)
+		tmpl, err = report.ParseFS(os.DirFS(filepath.Dir(*tmplFile)), filepath.Base(*tmplFile))
 		if err != nil {
-			fmt.Println("open failed:", err)
-			return
+			fatal(err)
 		}
 	}
 
-	scanner := bufio.NewScanner(f)
+	var all []report.Series
+	var times [][]time.Time
 
-	s := change.NewStream(*windowSize, *minSample, *blockSize, 0.995)
+	if len(fnames) == 0 {
+		log.Println("reading from stdin")
+		s, t, err := detect("stdin", os.Stdin, &opts)
+		if err != nil {
+			fatal(err)
+		}
+		all, times = append(all, s), append(times, t)
+	}
 
-	type graphPoints [2]float64
-	var graphData []graphPoints
-	var last []float64
+	for _, fname := range fnames {
+		f, err := os.Open(fname)
+		if err != nil {
+			fatal(fmt.Errorf("open failed: %v", err))
+		}
+		s, t, err := detect(fname, f, &opts)
+		f.Close()
+		if err != nil {
+			fatal(err)
+		}
+		all, times = append(all, s), append(times, t)
+	}
 
-	var changePoints []int
+	status := exitNoChange
+	for _, s := range all {
+		if len(s.ChangePoints) > 0 {
+			status = exitChange
+		}
+	}
 
-	var items int
+	if *quiet {
+		os.Exit(status)
+	}
 
-	for scanner.Scan() {
-		item, err := strconv.ParseFloat(scanner.Text(), 64)
-		if err != nil {
-			fmt.Printf("error parsing <%s>: %s\n", scanner.Text(), err)
-			continue
+	if *plot == "term" {
+		plotTerm(os.Stdout, all, 80)
+		os.Exit(status)
+	}
+
+	// the x axis is time only if every series has timestamps
+	timeAxis := true
+	for _, t := range times {
+		timeAxis = timeAxis && t != nil
+	}
+
+	// x returns the position of an offset into the i'th series on the x axis
+	x := func(i, offset int) float64 {
+		if timeAxis && offset < len(times[i]) {
+			return float64(times[i][offset].UnixMilli())
 		}
+		return float64(offset)
+	}
 
-		last = append(last, item)
-		items++
-		if items > 0 && items%*compressPoints == 0 {
-			sort.Float64s(last)
-			median := last[*compressPoints/2]
-			last = last[:0]
+	var changes [][]change.ChangePoint
+	for _, s := range all {
+		changes = append(changes, s.ChangePoints)
+	}
 
-			graphData = append(graphData, [2]float64{float64(items), median})
+	var common []int
+	for _, c := range change.ClusterPoints(changes, *tolerance) {
+		if n := c.Series(); n > 1 {
+			log.Printf("change at offset=%d-%d found in %d of %d series\n", c.Start, c.End, n, len(all))
+			common = append(common, (c.Start+c.End)/2)
 		}
+	}
 
-		r := s.Push(item)
+	var markings []report.Marking
+	for i, s := range all {
+		for _, cp := range s.ChangePoints {
+			markings = append(markings, report.Marking{X: x(i, cp.Offset), Color: "#000"})
+		}
+	}
+	for _, offset := range common {
+		markings = append(markings, report.Marking{X: x(0, offset), Color: "#d00"})
+	}
 
-		if r != nil {
-			diff := math.Abs(r.Difference / r.Before.Mean())
-			if r.Difference != 0 && diff > 0.06 {
-				log.Printf("difference found at offset=%d: %f %v\n", items-*windowSize+r.Index, diff, r)
-				changePoints = append(changePoints, items-*windowSize+r.Index)
+	if timeAxis {
+		for i := range all {
+			for j := range all[i].GraphData {
+				all[i].GraphData[j][0] = x(i, int(all[i].GraphData[j][0])-1)
 			}
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Printf("Error during scan: %v", err)
+	err := report.Write(os.Stdout, tmpl, &report.Data{
+		YMin:         *ymin,
+		Time:         timeAxis,
+		Series:       all,
+		ChangePoints: markings,
+	})
+	if err != nil {
+		fatal(err)
 	}
 
-	reportTmpl.Execute(os.Stdout, struct {
-		YMin         int
-		GraphData    []graphPoints
-		ChangePoints []int
-	}{
-		*ymin,
-		graphData,
-		changePoints,
-	})
+	os.Exit(status)
 }
 
-var reportTmpl = template.Must(template.New("report").Parse(`
-<html>
-<script src="//cdnjs.cloudflare.com/ajax/libs/jquery/2.0.3/jquery.min.js"></script>
-<script src="//cdnjs.cloudflare.com/ajax/libs/flot/0.8.2/jquery.flot.min.js"></script>
+// detect runs change detection over the items read from f, and returns the
+// timestamps of the items if the input has them
+func detect(label string, f io.Reader, opts *options) (report.Series, []time.Time, error) {
 
-<script type="text/javascript">
+	r := report.Series{Label: label}
 
-    var data = {{ .GraphData }};
+	in, err := readSeries(f, opts.format, &opts.numberFormat)
+	if err != nil {
+		return r, nil, fmt.Errorf("%s: error reading input: %v", label, err)
+	}
 
-    $(document).ready(function() {
-        $.plot($("#placeholder"), [data], {
-             yaxis: { min: {{ .YMin }} },
-             grid: {
-                markings: [
-                  {{ range .ChangePoints }}{ color: '#000', lineWidth: 1, xaxis: { from: {{ . }}, to: {{ . }} } },
-                  {{ end }}
-                ]
-              }
-           })
-        })
+	if !opts.from.IsZero() || !opts.to.IsZero() {
+		if in.times == nil {
+			log.Printf("%s: input has no timestamps; ignoring -from and -to\n", label)
+		} else {
+			in = in.between(opts.from, opts.to)
+		}
+	}
+	values := in.values
+
+	// the flags are validated here rather than by NewStream, which panics
+	s, err := change.NewStreamFromConfig(change.Config{
+		WindowSize:    opts.windowSize,
+		MinSampleSize: opts.minSample,
+		BlockSize:     opts.blockSize,
+		MinConfidence: 0.995,
+	})
+	if err != nil {
+		return r, nil, fmt.Errorf("%s: %v", label, err)
+	}
+	s.Counter = opts.counter
+
+	var last []float64
+
+	var items int
+
+	for _, item := range values {
+		last = append(last, item)
+		items++
+		if items > 0 && items%opts.compressPoints == 0 {
+			sort.Float64s(last)
+			median := last[opts.compressPoints/2]
+			last = last[:0]
 
-</script>
+			r.GraphData = append(r.GraphData, report.Point{float64(items), median})
+		}
 
-<body>
+		cp := s.Push(item)
 
-<div id="placeholder" style="width:1200px; height:400px"></div>
+		if cp != nil {
+			diff := math.Abs(cp.Difference / cp.Before.Mean())
+			if cp.Difference != 0 && diff > 0.06 {
+				log.Printf("%s: difference found at offset=%d (%d items ago): %f %v\n", label, cp.Offset, cp.Lag, diff, cp)
+				r.ChangePoints = append(r.ChangePoints, *cp)
+			}
+		}
+	}
 
-</body>
-</html>
-`))
+	return r, in.times, nil
+}
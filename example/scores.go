@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/offline"
+)
+
+// scores implements the scores subcommand, which writes a tidy CSV with a
+// row for each threshold and index of the series: the value, the score and
+// confidence of splitting the whole series there, and whether a change was
+// found there with that minimum confidence.  This lets thresholds be tuned
+// from one run.
+func scores(args []string) {
+	fs := flag.NewFlagSet("scores", flag.ExitOnError)
+	fname := fs.String("f", "", "file name (default stdin)")
+	minSample := fs.Int("ms", 30, "min sample size")
+	thresholds := fs.String("thresholds", "0.9,0.95,0.99,0.995,0.999", "comma-separated min confidences to detect changes with")
+	format := fs.String("input-format", formatAuto, "input format: auto, lines, csv, json, ndjson or graphite")
+	var nf numberFormat
+	nf.flags(fs)
+	fs.Parse(args)
+
+	var levels []float64
+	for _, s := range strings.Split(*thresholds, ",") {
+		c, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			fatal(fmt.Errorf("scores: invalid threshold %q", s))
+		}
+		levels = append(levels, c)
+	}
+
+	var r io.Reader = os.Stdin
+	if *fname != "" {
+		f, err := os.Open(*fname)
+		if err != nil {
+			fatal(fmt.Errorf("open failed: %v", err))
+		}
+		defer f.Close()
+		r = f
+	}
+
+	in, err := readSeries(r, *format, &nf)
+	if err != nil {
+		fatal(fmt.Errorf("error reading input: %v", err))
+	}
+
+	if err := writeScores(os.Stdout, in.values, *minSample, levels); err != nil {
+		fatal(err)
+	}
+
+	os.Exit(exitNoChange)
+}
+
+// writeScores writes the tidy CSV of the scores subcommand for values to w
+func writeScores(out io.Writer, values []float64, minSample int, levels []float64) error {
+	score, confidence := (&change.Detector{MinSampleSize: minSample}).Scores(values)
+
+	w := csv.NewWriter(out)
+	w.Write([]string{"threshold", "index", "value", "score", "confidence", "change"})
+
+	num := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+
+	for _, level := range levels {
+		found := make(map[int]bool)
+		for cp := range offline.Changes(values, &change.Detector{MinSampleSize: minSample, MinConfidence: level}) {
+			found[cp.Index] = true
+		}
+
+		for i, v := range values {
+			w.Write([]string{
+				num(level),
+				strconv.Itoa(i),
+				num(v),
+				num(score[i]),
+				num(confidence[i]),
+				strconv.FormatBool(found[i]),
+			})
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
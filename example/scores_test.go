@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestWriteScores(t *testing.T) {
+
+	var values []float64
+	for i := 0; i < 40; i++ {
+		v := float64(i % 2)
+		if i >= 20 {
+			v += 10
+		}
+		values = append(values, v)
+	}
+
+	var out strings.Builder
+	if err := writeScores(&out, values, 5, []float64{0.9, 0.99}); err != nil {
+		t.Fatalf("writeScores=%v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("writeScores wrote invalid CSV: %v", err)
+	}
+	if len(records) != 1+2*len(values) {
+		t.Fatalf("writeScores wrote %d rows, wanted %d", len(records), 1+2*len(values))
+	}
+	if got := strings.Join(records[0], ","); got != "threshold,index,value,score,confidence,change" {
+		t.Errorf("header=%q", got)
+	}
+
+	for _, rec := range records[1:] {
+		if want := rec[1] == "20"; (rec[5] == "true") != want {
+			t.Errorf("row %v: change=%s, wanted %v", rec, rec[5], want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/dgryski/go-change/report"
+	"github.com/dgryski/go-change/status"
+)
+
+// plotTerm writes each series to w as a sparkline at most width columns wide,
+// with its change points marked by a ^ on the line below
+func plotTerm(w io.Writer, all []report.Series, width int) {
+	for _, s := range all {
+		n := len(s.GraphData)
+		cols := min(n, width)
+
+		// column returns the column plotting the i'th graph point, as
+		// status.Sparkline does
+		column := func(i int) int { return i * cols / n }
+
+		values := make([]float64, n)
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for i, p := range s.GraphData {
+			values[i] = p[1]
+			lo, hi = math.Min(lo, p[1]), math.Max(hi, p[1])
+		}
+
+		marks := []byte(strings.Repeat(" ", cols))
+		for _, cp := range s.ChangePoints {
+			// the first graph point at or after the change
+			i := 0
+			for i < n-1 && int(s.GraphData[i][0]) <= cp.Offset {
+				i++
+			}
+			if cols > 0 {
+				marks[column(i)] = '^'
+			}
+		}
+
+		fmt.Fprintf(w, "%s (%.4g - %.4g)\n%s\n%s\n", s.Label, lo, hi, status.Sparkline(values, width), strings.TrimRight(string(marks), " "))
+		for _, cp := range s.ChangePoints {
+			fmt.Fprintf(w, "  change at offset=%d: %.4g -> %.4g\n", cp.Offset, cp.Before.Mean(), cp.After.Mean())
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package change_test
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/datasets"
+)
+
+func ExampleDetector_Check() {
+	nile := datasets.Nile()
+
+	d := change.Detector{MinSampleSize: 10, MinConfidence: 0.99}
+	if cp := d.Check(nile.Values); cp != nil {
+		fmt.Printf("change at %d: %.1f -> %.1f\n", cp.Index, cp.Before.Mean(), cp.After.Mean())
+	}
+	// Output: change at 28: 1097.8 -> 850.0
+}
+
+func ExampleDetector_CheckAll() {
+	ds := datasets.Synthetic()[4] // two steps, at 200 and 400
+
+	d := change.Detector{MinSampleSize: 30, MinConfidence: 0.9999}
+	for _, cp := range d.CheckAll(ds.Values) {
+		fmt.Printf("%+.0f near %d\n", cp.Difference, cp.Index/10*10)
+	}
+	// Output:
+	// +3 near 200
+	// -2 near 400
+}
+
+func ExampleNewStream() {
+	s := change.NewStream(100, 5, 10, 0.999)
+
+	for i := 0; i < 300; i++ {
+		v := 10.0 + float64(i%5)
+		if i >= 200 {
+			v += 10
+		}
+
+		if cp := s.Push(v); cp != nil {
+			fmt.Printf("change at offset %d, found %d items later\n", cp.Offset, cp.Lag)
+			break
+		}
+	}
+	// Output: change at offset 200, found 10 items later
+}
+
+func ExampleStream_Changes() {
+	series := make([]float64, 300)
+	for i := range series {
+		series[i] = float64(i % 5)
+		if i >= 150 {
+			series[i] += 10
+		}
+	}
+
+	s := change.NewStream(100, 5, 10, 0.999)
+	for cp := range s.Changes(slices.Values(series)) {
+		fmt.Println("change at offset", cp.Offset)
+		break
+	}
+	// Output: change at offset 150
+}
+
+func ExampleMonitor() {
+	m := change.NewMonitor(change.NewStream(40, 5, 5, 0.99), 0.99, 0.9)
+
+	for i := 0; i < 200; i++ {
+		v := 1.0 + 0.1*float64(i%2)
+		if i >= 60 && i < 100 {
+			v += 1
+		}
+
+		if sc := m.Push(v); sc != nil {
+			fmt.Println(sc.Kind, "at", sc.Offset)
+		}
+	}
+	// Output:
+	// ChangeStarted at 60
+	// ChangeEnded at 100
+}
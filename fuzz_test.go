@@ -0,0 +1,81 @@
+package change
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// fuzzMaxLen bounds the inputs, as SegmentTrends is quadratic in the window size
+const fuzzMaxLen = 4096
+
+// fuzzFloats decodes data as little-endian float64s, so the fuzzer can
+// produce NaNs, infinities and denormals
+func fuzzFloats(data []byte) []float64 {
+	r := make([]float64, len(data)/8)
+	for i := range r {
+		r[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	return r
+}
+
+func fuzzBytes(xs ...float64) []byte {
+	b := make([]byte, 8*len(xs))
+	for i, x := range xs {
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(x))
+	}
+	return b
+}
+
+func FuzzCheck(f *testing.F) {
+	f.Add([]byte{}, 0)
+	f.Add(fuzzBytes(1), 1)
+	f.Add(fuzzBytes(3, 3, 3, 3, 3, 3), 2)
+	f.Add(fuzzBytes(0, 0, 0, 1, 1, 1), 2)
+	f.Add(fuzzBytes(0, math.NaN(), 1, 2, 3, 4), 2)
+	f.Add(fuzzBytes(0, math.Inf(1), 1, math.Inf(-1), 3, 4), 1)
+	f.Add(fuzzBytes(1e308, -1e308, 1e308, -1e308), 1)
+	f.Add(fuzzBytes(0, 0, 0, 1, 1, 1), 100)
+	f.Add(fuzzBytes(0, 1), -63)
+
+	f.Fuzz(func(t *testing.T, data []byte, minSample int) {
+		if len(data) > 8*fuzzMaxLen {
+			return
+		}
+		window := fuzzFloats(data)
+		d := Detector{MinSampleSize: minSample % 64, AdjustAutocorrelation: true, SegmentTrends: true}
+
+		if cp := d.Check(window); cp != nil {
+			if cp.Index < 1 || cp.Index >= len(window) || cp.IndexLow > cp.Index || cp.IndexHigh < cp.Index {
+				t.Errorf("Check(%v)=%+v: index out of range", window, cp)
+			}
+		}
+
+		weights := make([]float64, len(window))
+		for i := range weights {
+			weights[i] = 1
+		}
+		d.CheckWeighted(window, weights)
+		d.CheckAll(window)
+		d.Scores(window)
+	})
+}
+
+func FuzzStream(f *testing.F) {
+	f.Add(fuzzBytes(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 4, 2, 1)
+	f.Add(fuzzBytes(math.NaN(), math.Inf(1), 0, 0, 0, 0), 2, 1, 1)
+
+	f.Fuzz(func(t *testing.T, data []byte, window, minSample, block int) {
+		c := Config{WindowSize: window % 256, MinSampleSize: minSample % 64, BlockSize: block % 64}
+		s, err := NewStreamFromConfig(c)
+		if err != nil {
+			return
+		}
+
+		for _, v := range fuzzFloats(data) {
+			if cp := s.Push(v); cp != nil && (cp.Offset < 0 || cp.Lag < 0) {
+				t.Errorf("Push: change point %+v out of range", cp)
+			}
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package change
+
+import (
+	"encoding/json"
+	"flag"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// golden is a serialized test vector.  The t-test confidence is omitted:
+// MinConfidence is zero so every split with a difference is reported, and
+// the remaining fields depend only on this package.
+type golden struct {
+	MinSampleSize int           `json:"min_sample_size"`
+	Input         []float64     `json:"input"`
+	Want          *goldenResult `json:"want"`
+}
+
+type goldenResult struct {
+	Index      int         `json:"index"`
+	IndexLow   int         `json:"index_low"`
+	IndexHigh  int         `json:"index_high"`
+	Difference float64     `json:"difference"`
+	Score      float64     `json:"score"`
+	Before     goldenStats `json:"before"`
+	After      goldenStats `json:"after"`
+}
+
+type goldenStats struct {
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	N        int     `json:"n"`
+}
+
+func goldenInputs() map[string]golden {
+	rnd := rand.New(rand.NewSource(1))
+
+	series := func(n int, f func(i int) float64) []float64 {
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = f(i)
+		}
+		return s
+	}
+
+	return map[string]golden{
+		"step": {10, series(100, func(i int) float64 {
+			if i >= 60 {
+				return 3 + rnd.NormFloat64()
+			}
+			return rnd.NormFloat64()
+		}), nil},
+		"ramp":  {5, series(50, func(i int) float64 { return float64(i) }), nil},
+		"flat":  {5, series(40, func(i int) float64 { return 7 }), nil},
+		"noise": {20, series(200, func(i int) float64 { return 100 + 10*rnd.NormFloat64() }), nil},
+	}
+}
+
+func TestGolden(t *testing.T) {
+
+	dir := filepath.Join("testdata", "golden")
+
+	if *update {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, g := range goldenInputs() {
+			g.Want = goldenCheck(g)
+			buf, err := json.MarshalIndent(g, "", "\t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, name+".json"), append(buf, '\n'), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no golden files found in %s: %v", dir, err)
+	}
+
+	for _, file := range files {
+		buf, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var g golden
+		if err := json.Unmarshal(buf, &g); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		got := goldenCheck(g)
+		if !goldenEqual(got, g.Want) {
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(g.Want)
+			t.Errorf("%s: Check=%s, wanted %s", file, gotJSON, wantJSON)
+		}
+	}
+}
+
+func goldenCheck(g golden) *goldenResult {
+	d := Detector{MinSampleSize: g.MinSampleSize}
+	cp := d.Check(g.Input)
+	if cp == nil {
+		return nil
+	}
+
+	stats := func(s Stats) goldenStats { return goldenStats{s.mean, s.variance, s.n} }
+
+	return &goldenResult{
+		Index:      cp.Index,
+		IndexLow:   cp.IndexLow,
+		IndexHigh:  cp.IndexHigh,
+		Difference: cp.Difference,
+		Score:      cp.Score,
+		Before:     stats(cp.Before),
+		After:      stats(cp.After),
+	}
+}
+
+func goldenEqual(a, b *goldenResult) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	close := func(x, y float64) bool {
+		return math.Abs(x-y) <= 1e-9*math.Max(1, math.Abs(y))
+	}
+
+	return a.Index == b.Index && a.IndexLow == b.IndexLow && a.IndexHigh == b.IndexHigh &&
+		close(a.Difference, b.Difference) && close(a.Score, b.Score) &&
+		close(a.Before.Mean, b.Before.Mean) && close(a.Before.Variance, b.Before.Variance) && a.Before.N == b.Before.N &&
+		close(a.After.Mean, b.After.Mean) && close(a.After.Variance, b.After.Variance) && a.After.N == b.After.N
+}
@@ -0,0 +1,95 @@
+// Package grafana posts detected change points as Grafana annotations
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+// Client posts annotations to the Grafana HTTP API
+type Client struct {
+	// URL is the base URL of the Grafana server
+	URL string
+
+	// Token is a service account token or API key
+	Token string
+
+	// DashboardUID and PanelID restrict the annotation to a dashboard or
+	// panel.  If empty, an organization-wide annotation is created.
+	DashboardUID string
+	PanelID      int64
+
+	// Tags are added to every annotation
+	Tags []string
+
+	// HTTPClient is used to make requests.  If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+type annotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      int64    `json:"panelId,omitempty"`
+	Time         int64    `json:"time"`
+	TimeEnd      int64    `json:"timeEnd,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Text         string   `json:"text"`
+}
+
+// Annotate posts an annotation for the change point cp found in series and
+// detected at the given time.  If the change point has a timestamp, the
+// annotation is a region from the start of the change to its detection;
+// otherwise it is a point at the detection time.
+func (c *Client) Annotate(ctx context.Context, series string, cp *change.ChangePoint, detected time.Time) error {
+
+	a := annotation{
+		DashboardUID: c.DashboardUID,
+		PanelID:      c.PanelID,
+		Time:         detected.UnixMilli(),
+		Tags:         append([]string{series}, c.Tags...),
+		Text:         fmt.Sprintf("%s: change of %+g (confidence %.4f)", series, cp.Difference, cp.Confidence),
+	}
+
+	if !cp.Time.IsZero() && cp.Time.Before(detected) {
+		a.Time, a.TimeEnd = cp.Time.UnixMilli(), detected.UnixMilli()
+	}
+
+	body, err := json.Marshal(&a)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimRight(c.URL, "/")+"/api/annotations", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("grafana: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestAnnotate(t *testing.T) {
+
+	var got annotation
+	var auth string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/annotations" || r.Method != "POST" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"id":1,"message":"Annotation added"}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{URL: srv.URL + "/", Token: "secret", DashboardUID: "abc", Tags: []string{"change"}}
+
+	detected := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cp := &change.ChangePoint{Difference: 2, Confidence: 0.999, Time: detected.Add(-time.Minute)}
+
+	if err := c.Annotate(context.Background(), "cpu", cp, detected); err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization=%q", auth)
+	}
+
+	if got.Time != cp.Time.UnixMilli() || got.TimeEnd != detected.UnixMilli() {
+		t.Errorf("annotation region %d-%d, wanted %d-%d", got.Time, got.TimeEnd, cp.Time.UnixMilli(), detected.UnixMilli())
+	}
+
+	if got.DashboardUID != "abc" || len(got.Tags) != 2 || got.Tags[0] != "cpu" {
+		t.Errorf("annotation=%+v", got)
+	}
+
+	c.URL = srv.URL + "/missing"
+	if err := c.Annotate(context.Background(), "cpu", cp, detected); err == nil {
+		t.Errorf("Annotate to bad URL succeeded")
+	}
+}
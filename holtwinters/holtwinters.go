@@ -0,0 +1,139 @@
+// Package holtwinters implements Holt-Winters forecasting for seasonal change detection
+/*
+Model is an additive Holt-Winters (triple exponential smoothing) forecaster.
+Stream feeds the one-step-ahead forecast errors of a Model into a
+change.Stream, so that the level, trend and seasonality the model has learned
+are not themselves reported as changes.
+*/
+package holtwinters
+
+import "github.com/dgryski/go-change"
+
+// Model is an additive Holt-Winters forecaster
+type Model struct {
+	// Alpha, Beta and Gamma are the smoothing factors for the level, trend and seasonal components, all in [0,1]
+	Alpha, Beta, Gamma float64
+
+	period int
+
+	level  float64
+	trend  float64
+	season []float64
+	t      int
+
+	// init collects the first two seasons, which are used to initialize the model
+	init []float64
+}
+
+// New returns a model for data with the given seasonal period
+func New(alpha, beta, gamma float64, period int) *Model {
+	return &Model{
+		Alpha:  alpha,
+		Beta:   beta,
+		Gamma:  gamma,
+		period: period,
+		init:   make([]float64, 0, 2*period),
+	}
+}
+
+// Ready reports whether the model has seen enough data, two full seasons, to make forecasts
+func (m *Model) Ready() bool { return m.season != nil }
+
+// Forecast returns the forecast for the next observation.  It returns 0 until the model is ready.
+func (m *Model) Forecast() float64 {
+	if !m.Ready() {
+		return 0
+	}
+	return m.level + m.trend + m.season[m.t%m.period]
+}
+
+// Update adds an observation to the model
+func (m *Model) Update(x float64) {
+	if !m.Ready() {
+		m.init = append(m.init, x)
+		if len(m.init) == 2*m.period {
+			m.initialize()
+		}
+		return
+	}
+
+	idx := m.t % m.period
+	s := m.season[idx]
+
+	level := m.Alpha*(x-s) + (1-m.Alpha)*(m.level+m.trend)
+	m.trend = m.Beta*(level-m.level) + (1-m.Beta)*m.trend
+	m.level = level
+	m.season[idx] = m.Gamma*(x-level) + (1-m.Gamma)*s
+	m.t++
+}
+
+// initialize sets the initial components from the first season and the
+// trend between the first two, then updates the model with the second season.
+func (m *Model) initialize() {
+	p := m.period
+	first, second := m.init[:p], m.init[p:]
+
+	mean1, mean2 := mean(first), mean(second)
+
+	m.level = mean1
+	m.trend = (mean2 - mean1) / float64(p)
+	m.season = make([]float64, p)
+	for i, v := range first {
+		m.season[i] = v - mean1
+	}
+
+	// the components now describe the end of the first season
+	m.level += m.trend * float64(p-1)
+
+	for _, v := range second {
+		m.Update(v)
+	}
+
+	m.init = nil
+}
+
+func mean(xs []float64) float64 {
+	var sum float64
+	for _, v := range xs {
+		sum += v
+	}
+	return sum / float64(len(xs))
+}
+
+// Stream runs change detection on the forecast errors of a Holt-Winters model
+type Stream struct {
+	model  *Model
+	stream *change.Stream
+
+	// warmup is the number of items used to initialize the model
+	warmup int
+}
+
+// NewStream returns a stream that detects changes in the errors of model's
+// forecasts using s.  Offsets of the change points reported are adjusted to
+// count the items used to initialize the model.
+func NewStream(model *Model, s *change.Stream) *Stream {
+	return &Stream{model: model, stream: s}
+}
+
+// Push adds an observation, updates the model and calls the change detector on the forecast error
+func (s *Stream) Push(x float64) *change.ChangePoint {
+	if !s.model.Ready() {
+		s.model.Update(x)
+		s.warmup++
+		return nil
+	}
+
+	err := x - s.model.Forecast()
+	s.model.Update(x)
+
+	cp := s.stream.Push(err)
+	if cp != nil {
+		cp.Offset += s.warmup
+	}
+
+	return cp
+}
+
+// Model returns the underlying forecaster
+func (s *Stream) Model() *Model { return s.model }
@@ -0,0 +1,54 @@
+package holtwinters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+func seasonal(i int) float64 {
+	return 100 + 0.05*float64(i) + 20*math.Sin(2*math.Pi*float64(i)/24)
+}
+
+func TestForecast(t *testing.T) {
+
+	m := New(0.3, 0.05, 0.3, 24)
+
+	var maxErr float64
+	for i := 0; i < 24*20; i++ {
+		x := seasonal(i)
+		if i >= 24*10 {
+			maxErr = math.Max(maxErr, math.Abs(m.Forecast()-x))
+		}
+		m.Update(x)
+	}
+
+	if maxErr > 1 {
+		t.Errorf("forecast error up to %f on noiseless seasonal data, wanted < 1", maxErr)
+	}
+}
+
+func TestStream(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	s := NewStream(New(0.1, 0.01, 0.2, 24), change.NewStream(60, 10, 6, 0.999))
+
+	var first *change.ChangePoint
+	for i := 0; i < 24*30; i++ {
+		x := seasonal(i) + rnd.NormFloat64()
+		if i >= 400 {
+			x += 15
+		}
+
+		if cp := s.Push(x); cp != nil && first == nil {
+			first = cp
+		}
+	}
+
+	if first == nil || first.Offset < 395 || first.Offset > 405 {
+		t.Errorf("Stream first change=%v, wanted one at about 400", first)
+	}
+}
@@ -0,0 +1,16 @@
+package change
+
+import "iter"
+
+// Changes returns an iterator that pushes each of items to the stream and
+// yields the change points found.  Items are consumed lazily, so ranging
+// over a long or unbounded sequence does not buffer it.
+func (s *Stream) Changes(items iter.Seq[float64]) iter.Seq[ChangePoint] {
+	return func(yield func(ChangePoint) bool) {
+		for item := range items {
+			if cp := s.Push(item); cp != nil && !yield(*cp) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package change
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStreamChanges(t *testing.T) {
+
+	var series []float64
+	for i := 0; i < 200; i++ {
+		v := float64(i % 2)
+		if i >= 100 {
+			v += 10
+		}
+		series = append(series, v)
+	}
+
+	s := NewStream(40, 5, 5, 0.95)
+
+	var pushed int
+	items := func(yield func(float64) bool) {
+		for _, v := range series {
+			pushed++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+
+	for cp := range s.Changes(items) {
+		if cp.Offset != 100 {
+			t.Errorf("Changes yielded change at %d, wanted 100", cp.Offset)
+		}
+		break
+	}
+
+	// items after the first change must not have been consumed
+	if pushed == len(series) {
+		t.Errorf("Changes consumed all %d items", pushed)
+	}
+
+	if got := slices.Collect(NewStream(40, 5, 5, 0.95).Changes(slices.Values(series[:100]))); len(got) != 0 {
+		t.Errorf("Changes on constant series=%v, wanted none", got)
+	}
+}
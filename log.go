@@ -0,0 +1,27 @@
+package change
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logCheck records the best split of a window of n items
+func (d *Detector) logCheck(n, index int, score, conf float64) {
+	if d.Logger == nil || !d.Logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	d.Logger.Debug("change detector checked window",
+		"n", n,
+		"index", index,
+		"score", score,
+		"confidence", conf,
+		"triggered", conf > d.MinConfidence,
+	)
+}
+
+func (s *Stream) debug(msg string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Debug(msg, args...)
+	}
+}
@@ -0,0 +1,43 @@
+package change
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	s := NewStream(20, 5, 5, 0.95)
+	s.Logger = logger
+	s.Detector().Logger = logger
+
+	var r *ChangePoint
+	for i := 0; i < 100 && r == nil; i++ {
+		v := float64(i % 2)
+		if i >= 50 {
+			v += 10
+		}
+		r = s.Push(v)
+	}
+
+	if r == nil {
+		t.Fatalf("Stream found no change")
+	}
+
+	for _, want := range []string{
+		"stream warming up",
+		"change detector checked window",
+		"triggered=false",
+		"triggered=true",
+		"change point found",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package matched_test
+
+import (
+	"fmt"
+
+	"github.com/dgryski/go-change/matched"
+)
+
+func ExampleFind() {
+	series := make([]float64, 200)
+	for i := range series {
+		series[i] = float64(i % 2)
+		if i >= 50 {
+			series[i] += 10
+		}
+		if i == 150 {
+			series[i] += 20
+		}
+	}
+
+	for _, m := range matched.Find(series, matched.Bank(16), 0.9) {
+		fmt.Printf("%s at %d\n", m.Template, m.Index)
+	}
+	// Output:
+	// step at 50
+	// spike at 150
+}
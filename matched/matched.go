@@ -0,0 +1,211 @@
+// Package matched classifies changes by correlating a series with a bank of templates
+/*
+Each window of a series is compared with every template in a bank, such as a
+step, a spike and a ramp, using the Pearson correlation.  The strongest
+correlations that don't overlap are reported as matches, so a series can be
+described as "spike at 1042, ramp starting at 1100".  Matching is O(n*w) per
+template for templates of width w.
+*/
+package matched
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Template is a change shape to look for
+type Template struct {
+	Name string
+
+	// Shape is the template itself; only its shape matters, not its
+	// level or scale
+	Shape []float64
+
+	// Offset is the index in Shape at which the change is reported, such
+	// as the first item after a step
+	Offset int
+}
+
+// checkWidth panics if w is too narrow for a template: a template of width 1
+// is constant, and so correlates with nothing
+func checkWidth(w int) {
+	if w < 2 {
+		panic(fmt.Sprintf("matched: invalid template width %d", w))
+	}
+}
+
+// Step returns a template of width w for a change in level.  A negative
+// correlation with it is a step down.  It panics if w is less than 2.
+func Step(w int) Template {
+	checkWidth(w)
+	shape := make([]float64, w)
+	for i := w / 2; i < w; i++ {
+		shape[i] = 1
+	}
+	return Template{Name: "step", Shape: shape, Offset: w / 2}
+}
+
+// Spike returns a template of width w for a single outlier in the middle of
+// the window.  It panics if w is less than 2.
+func Spike(w int) Template {
+	checkWidth(w)
+	shape := make([]float64, w)
+	shape[w/2] = 1
+	return Template{Name: "spike", Shape: shape, Offset: w / 2}
+}
+
+// Ramp returns a template of width w for a linear trend starting at the
+// beginning of the window.  It panics if w is less than 2.
+func Ramp(w int) Template {
+	checkWidth(w)
+	shape := make([]float64, w)
+	for i := range shape {
+		shape[i] = float64(i)
+	}
+	return Template{Name: "ramp", Shape: shape, Offset: 0}
+}
+
+// Bank returns the step, spike and ramp templates of width w.  It panics if w
+// is less than 2.
+func Bank(w int) []Template {
+	return []Template{Step(w), Spike(w), Ramp(w)}
+}
+
+// Match is a place where a template matched the series
+type Match struct {
+	// Template is the name of the template that matched
+	Template string
+
+	// Index is the index in the series of the template's Offset
+	Index int
+
+	// Correlation is the Pearson correlation of the template with the
+	// series; it is negative if the series has the opposite shape, such as
+	// a step down or a dip
+	Correlation float64
+}
+
+// Find returns the matches of the templates in series with an absolute
+// correlation of at least threshold, in order of index.  Every template is
+// tried at every position, and a match is dropped if its window overlaps that
+// of a stronger one, so each part of the series is matched by at most one
+// template.  Of equally strong matches, the one starting earliest in the
+// series wins, and then the one earliest in the bank.
+func Find(series []float64, bank []Template, threshold float64) []Match {
+
+	type candidate struct {
+		Match
+		start, end int
+
+		// template is the index of the template in the bank
+		template int
+	}
+
+	var sum, sumsq []float64
+	var s, ss float64
+	sum = append(sum, 0)
+	sumsq = append(sumsq, 0)
+	for _, v := range series {
+		s += v
+		ss += v * v
+		sum = append(sum, s)
+		sumsq = append(sumsq, ss)
+	}
+
+	var candidates []candidate
+	for ti, t := range bank {
+		shape := normalize(t.Shape)
+		if shape == nil {
+			continue
+		}
+
+		w := len(shape)
+		for start := 0; start+w <= len(series); start++ {
+			n := float64(w)
+			ws, wss := sum[start+w]-sum[start], sumsq[start+w]-sumsq[start]
+			norm := math.Sqrt(wss - ws*ws/n)
+			if norm <= 1e-12*math.Max(1, math.Abs(ws)) {
+				continue
+			}
+
+			var dot float64
+			for i, c := range shape {
+				dot += c * series[start+i]
+			}
+
+			corr := dot / norm
+			if math.Abs(corr) < threshold {
+				continue
+			}
+
+			candidates = append(candidates, candidate{
+				Match:    Match{Template: t.Name, Index: start + t.Offset, Correlation: corr},
+				start:    start,
+				end:      start + w,
+				template: ti,
+			})
+		}
+	}
+
+	// strongest first; ties go to the earliest position and then the
+	// earliest template in the bank
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := &candidates[i], &candidates[j]
+		if ca, cb := math.Abs(a.Correlation), math.Abs(b.Correlation); ca != cb {
+			return ca > cb
+		}
+		if a.start != b.start {
+			return a.start < b.start
+		}
+		return a.template < b.template
+	})
+
+	var accepted []candidate
+	for _, c := range candidates {
+		overlaps := false
+		for _, a := range accepted {
+			if c.start < a.end && a.start < c.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			accepted = append(accepted, c)
+		}
+	}
+
+	matches := make([]Match, len(accepted))
+	for i, a := range accepted {
+		matches[i] = a.Match
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Index < matches[j].Index })
+
+	return matches
+}
+
+// normalize returns shape scaled to zero mean and unit norm, or nil if it is constant
+func normalize(shape []float64) []float64 {
+	var mean float64
+	for _, v := range shape {
+		mean += v
+	}
+	mean /= float64(len(shape))
+
+	r := make([]float64, len(shape))
+	var norm float64
+	for i, v := range shape {
+		r[i] = v - mean
+		norm += r[i] * r[i]
+	}
+
+	if norm == 0 {
+		return nil
+	}
+
+	norm = math.Sqrt(norm)
+	for i := range r {
+		r[i] /= norm
+	}
+	return r
+}
@@ -0,0 +1,109 @@
+package matched
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	series := make([]float64, 900)
+	for i := range series {
+		v := 0.05 * rnd.NormFloat64()
+		switch {
+		case i >= 700:
+			v -= 5
+		case i >= 532:
+			v += 8
+		case i >= 500:
+			v += 5 + 3*float64(i-500)/32
+		case i >= 100:
+			v += 5
+		}
+		if i == 300 {
+			v += 10
+		}
+		series[i] = v
+	}
+
+	matches := Find(series, Bank(32), 0.9)
+
+	var tests = []struct {
+		template string
+		index    int
+		positive bool
+	}{
+		{"step", 100, true},
+		{"spike", 300, true},
+		{"ramp", 500, true},
+		{"step", 700, false},
+	}
+
+	if len(matches) != len(tests) {
+		t.Fatalf("Find=%v, wanted %d matches", matches, len(tests))
+	}
+
+	for i, tt := range tests {
+		m := matches[i]
+		if m.Template != tt.template || math.Abs(float64(m.Index-tt.index)) > 2 || (m.Correlation > 0) != tt.positive {
+			t.Errorf("match %d=%+v, wanted %s at %d (positive=%v)", i, m, tt.template, tt.index, tt.positive)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+
+	if normalize([]float64{3, 3, 3}) != nil {
+		t.Errorf("normalize of a constant shape should be nil")
+	}
+
+	r := normalize([]float64{0, 1, 2})
+	var sum, sumsq float64
+	for _, v := range r {
+		sum += v
+		sumsq += v * v
+	}
+	if math.Abs(sum) > 1e-12 || math.Abs(sumsq-1) > 1e-12 {
+		t.Errorf("normalize=%v, wanted zero mean and unit norm", r)
+	}
+}
+
+func TestFindTies(t *testing.T) {
+
+	series := make([]float64, 40)
+	for i := 20; i < len(series); i++ {
+		series[i] = 1
+	}
+
+	// identical templates tie, and the first in the bank wins
+	first, second := Step(8), Step(8)
+	first.Name, second.Name = "first", "second"
+
+	matches := Find(series, []Template{first, second}, 0.9)
+	if len(matches) != 1 || matches[0].Template != "first" || matches[0].Index != 20 {
+		t.Errorf("Find=%+v, wanted one match of the first template at 20", matches)
+	}
+}
+
+func TestTemplateWidth(t *testing.T) {
+
+	for _, w := range []int{-1, 0, 1} {
+		for _, f := range []func(int) Template{Step, Spike, Ramp} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("template of width %d did not panic", w)
+					}
+				}()
+				f(w)
+			}()
+		}
+	}
+
+	if b := Bank(2); len(b) != 3 {
+		t.Errorf("Bank(2)=%v, wanted 3 templates", b)
+	}
+}
@@ -0,0 +1,212 @@
+package change
+
+import "math"
+
+// StateKind is the kind of a StateChange
+type StateKind int
+
+const (
+	// ChangeStarted is reported when a change is first detected
+	ChangeStarted StateKind = iota + 1
+
+	// ChangeEnded is reported when the series changes back
+	ChangeEnded
+
+	// ChangeUnstable is reported instead of a transition when the series
+	// is flapping between levels
+	ChangeUnstable
+
+	// ChangeStable is reported when a flapping series has had no
+	// transitions for FlapWindow items
+	ChangeStable
+)
+
+func (k StateKind) String() string {
+	switch k {
+	case ChangeStarted:
+		return "ChangeStarted"
+	case ChangeEnded:
+		return "ChangeEnded"
+	case ChangeUnstable:
+		return "ChangeUnstable"
+	case ChangeStable:
+		return "ChangeStable"
+	}
+	return "StateKind(?)"
+}
+
+// StateChange is a transition reported by a Monitor
+type StateChange struct {
+	Kind StateKind
+
+	// ChangePoint is the change point that caused the transition
+	ChangePoint
+
+	// Start is the offset of the change that started the episode
+	Start int
+
+	// Duration is the number of items between the start and the end of
+	// the episode.  It is only set for ChangeEnded.
+	Duration int
+
+	// Baseline is the mean before the change that started the episode.  It
+	// is only set for ChangeStarted and ChangeEnded.
+	Baseline float64
+
+	// Flips is the number of transitions within the flap window, Period
+	// the mean number of items between them and Amplitude their mean
+	// absolute difference.  They are only set for ChangeUnstable.
+	Flips     int
+	Period    float64
+	Amplitude float64
+}
+
+// Monitor tracks whether a stream is in a changed state.  A change must be
+// detected with at least the Enter confidence to start an episode, and a
+// change back in the opposite direction with at least the Clear confidence
+// ends it, so a transient regression that recovers is reported once with its
+// duration instead of as two unrelated change points.  Clear is usually lower
+// than Enter.
+type Monitor struct {
+	stream *Stream
+
+	Enter float64
+	Clear float64
+
+	// Tolerance, if non-zero, is the fraction of the original change that
+	// may remain for a change back to end the episode.  A change back that
+	// leaves the series further from the baseline is a partial recovery and
+	// the episode continues.
+	Tolerance float64
+
+	active  bool
+	started ChangePoint
+
+	// last is the offset of the change point that caused the most recent transition
+	last    int
+	hasLast bool
+
+	// FlapCount is the number of transitions within FlapWindow items after
+	// which the series is considered to be flapping.  A single
+	// ChangeUnstable is then reported in place of further transitions,
+	// until ChangeStable once FlapWindow items pass without one.  If zero,
+	// flapping is not detected.
+	FlapCount  int
+	FlapWindow int
+
+	flips    []ChangePoint
+	unstable bool
+}
+
+// NewMonitor returns a monitor for s.  The minimum confidence of the
+// stream's detector is lowered to the lesser of enter and clear.
+func NewMonitor(s *Stream, enter, clear float64) *Monitor {
+	s.detector.MinConfidence = enter
+	if clear < enter {
+		s.detector.MinConfidence = clear
+	}
+
+	return &Monitor{stream: s, Enter: enter, Clear: clear}
+}
+
+// Active reports whether the monitor is currently in a changed state
+func (m *Monitor) Active() bool { return m.active }
+
+// Push adds a float to the underlying stream and returns any state transition
+func (m *Monitor) Push(item float64) *StateChange {
+	cp := m.stream.Push(item)
+
+	if m.unstable && m.stream.items-m.last >= m.FlapWindow {
+		last := m.flips[len(m.flips)-1]
+		m.unstable, m.flips = false, m.flips[:0]
+		return &StateChange{Kind: ChangeStable, ChangePoint: last, Start: last.Offset}
+	}
+
+	if cp == nil {
+		return nil
+	}
+
+	sc := m.transition(cp)
+	if sc == nil || m.FlapCount <= 0 {
+		return sc
+	}
+
+	return m.flap(sc)
+}
+
+// transition returns the state transition caused by cp, if any
+func (m *Monitor) transition(cp *ChangePoint) *StateChange {
+
+	// The stream reports a change for as long as it remains in the window.
+	// Change points closer to the last transition than the detector's
+	// minimum sample size are taken to be the same change.
+	minSampleSize := m.stream.detector.minSampleSize()
+	if m.hasLast && cp.Offset < m.last+minSampleSize {
+		return nil
+	}
+
+	if !m.active {
+		if cp.Confidence < m.Enter {
+			return nil
+		}
+
+		m.active, m.started = true, *cp
+		m.last, m.hasLast = cp.Offset, true
+		return &StateChange{Kind: ChangeStarted, ChangePoint: *cp, Start: cp.Offset, Baseline: cp.Before.Mean()}
+	}
+
+	if cp.Difference*m.started.Difference > 0 || cp.Confidence < m.Clear {
+		return nil
+	}
+
+	if m.Tolerance > 0 && math.Abs(cp.After.Mean()-m.started.Before.Mean()) > m.Tolerance*math.Abs(m.started.Difference) {
+		// only a partial recovery
+		return nil
+	}
+
+	m.active = false
+	m.last = cp.Offset
+	return &StateChange{
+		Kind:        ChangeEnded,
+		ChangePoint: *cp,
+		Start:       m.started.Offset,
+		Duration:    cp.Offset - m.started.Offset,
+		Baseline:    m.started.Before.Mean(),
+	}
+}
+
+// flap records the transition sc, and returns it unless the series is flapping
+func (m *Monitor) flap(sc *StateChange) *StateChange {
+	m.flips = append(m.flips, sc.ChangePoint)
+
+	i := 0
+	for i < len(m.flips) && m.flips[i].Offset <= sc.Offset-m.FlapWindow {
+		i++
+	}
+	m.flips = append(m.flips[:0], m.flips[i:]...)
+
+	if m.unstable {
+		return nil
+	}
+
+	if len(m.flips) < m.FlapCount {
+		return sc
+	}
+
+	m.unstable = true
+
+	first, last := m.flips[0], m.flips[len(m.flips)-1]
+	var amplitude float64
+	for _, f := range m.flips {
+		amplitude += math.Abs(f.Difference)
+	}
+
+	return &StateChange{
+		Kind:        ChangeUnstable,
+		ChangePoint: last,
+		Start:       first.Offset,
+		Flips:       len(m.flips),
+		Period:      float64(last.Offset-first.Offset) / float64(len(m.flips)-1),
+		Amplitude:   amplitude / float64(len(m.flips)),
+	}
+}
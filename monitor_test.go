@@ -0,0 +1,117 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMonitor(t *testing.T) {
+
+	m := NewMonitor(NewStream(40, 5, 5, 0.99), 0.99, 0.9)
+
+	var changes []*StateChange
+	for i := 0; i < 200; i++ {
+		v := 1.0 + 0.1*float64(i%2)
+		if i >= 60 && i < 100 {
+			v += 1
+		}
+		if r := m.Push(v); r != nil {
+			changes = append(changes, r)
+		}
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Monitor reported %d transitions, wanted 2: %v", len(changes), changes)
+	}
+
+	if changes[0].Kind != ChangeStarted || changes[0].Start != 60 {
+		t.Errorf("first transition=%v %d, wanted ChangeStarted at 60", changes[0].Kind, changes[0].Start)
+	}
+
+	if changes[1].Kind != ChangeEnded || changes[1].Duration != 40 {
+		t.Errorf("second transition=%v duration %d, wanted ChangeEnded after 40", changes[1].Kind, changes[1].Duration)
+	}
+
+	if m.Active() {
+		t.Errorf("Monitor still active after recovery")
+	}
+}
+
+func TestMonitorFlap(t *testing.T) {
+
+	m := NewMonitor(NewStream(40, 5, 5, 0.99), 0.99, 0.9)
+	m.FlapCount = 4
+	m.FlapWindow = 200
+
+	var changes []*StateChange
+	for i := 0; i < 800; i++ {
+		v := 1.0 + 0.1*float64(i%2)
+
+		// flapping between two levels every 30 items, then settling
+		if i < 300 && i/30%2 == 1 {
+			v += 1
+		}
+		if r := m.Push(v); r != nil {
+			changes = append(changes, r)
+		}
+	}
+
+	var kinds []StateKind
+	for _, c := range changes {
+		kinds = append(kinds, c.Kind)
+	}
+
+	want := []StateKind{ChangeStarted, ChangeEnded, ChangeStarted, ChangeUnstable, ChangeStable}
+	if len(kinds) != len(want) {
+		t.Fatalf("Monitor reported %v, wanted %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("Monitor reported %v, wanted %v", kinds, want)
+		}
+	}
+
+	u := changes[3]
+	if u.Flips != 4 || u.Start != 30 || u.Period != 30 || u.Amplitude < 0.9 || u.Amplitude > 1.1 {
+		t.Errorf("ChangeUnstable flips=%d start=%d period=%v amplitude=%v, wanted 4 30 30 1", u.Flips, u.Start, u.Period, u.Amplitude)
+	}
+}
+
+func TestMonitorTolerance(t *testing.T) {
+
+	var tests = []struct {
+		recovery float64
+		ended    bool
+	}{
+		{0, true},    // back to the baseline
+		{0.05, true}, // within 10% of the change
+		{0.5, false}, // half the change remains
+	}
+
+	for _, tt := range tests {
+		m := NewMonitor(NewStream(40, 5, 5, 0.99), 0.99, 0.9)
+		m.Tolerance = 0.1
+
+		var changes []*StateChange
+		for i := 0; i < 200; i++ {
+			v := 1.0 + 0.01*float64(i%2)
+			if i >= 60 {
+				v += 1
+			}
+			if i >= 100 {
+				v -= 1 - tt.recovery
+			}
+			if r := m.Push(v); r != nil {
+				changes = append(changes, r)
+			}
+		}
+
+		if tt.ended {
+			if len(changes) != 2 || changes[1].Kind != ChangeEnded || changes[1].Duration != 40 || math.Abs(changes[1].Baseline-1.005) > 0.01 {
+				t.Errorf("recovery to %v: transitions=%v, wanted ChangeEnded after 40 with baseline 1", tt.recovery, changes)
+			}
+		} else if len(changes) != 1 || !m.Active() {
+			t.Errorf("recovery to %v: transitions=%v, wanted the episode to continue", tt.recovery, changes)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package change
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// NonFinitePolicy is how NaN and infinite values, such as the result of a
+// division by zero upstream, are handled
+type NonFinitePolicy int
+
+const (
+	// KeepNonFinite passes the value through unchanged.  NaNs and
+	// infinities in the window make the statistics of a check NaN, so
+	// changes are not reliably found until they leave the window.
+	KeepNonFinite NonFinitePolicy = iota
+
+	// SkipNonFinite drops the value, as if it was never pushed
+	SkipNonFinite
+
+	// ClampNonFinite replaces an infinity with the largest or smallest
+	// finite value seen, in the whole series for CleanNonFinite and so far
+	// for a Stream.  NaNs are dropped.
+	ClampNonFinite
+
+	// CarryLastNonFinite repeats the previous finite value, or drops the
+	// value if there is none
+	CarryLastNonFinite
+
+	// RejectNonFinite drops the value and reports it as an error wrapping
+	// ErrNonFinite
+	RejectNonFinite
+)
+
+func (p NonFinitePolicy) String() string {
+	switch p {
+	case KeepNonFinite:
+		return "KeepNonFinite"
+	case SkipNonFinite:
+		return "SkipNonFinite"
+	case ClampNonFinite:
+		return "ClampNonFinite"
+	case CarryLastNonFinite:
+		return "CarryLastNonFinite"
+	case RejectNonFinite:
+		return "RejectNonFinite"
+	}
+	return "NonFinitePolicy(?)"
+}
+
+// ErrNonFinite is reported for NaN or infinite values under RejectNonFinite
+var ErrNonFinite = errors.New("change: non-finite value")
+
+func finite(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) }
+
+// CleanNonFinite returns a copy of series with its non-finite values handled
+// according to policy, for use before offline detection.  Under
+// RejectNonFinite it returns an error for the first non-finite value.
+func CleanNonFinite(series []float64, policy NonFinitePolicy) ([]float64, error) {
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range series {
+		if finite(v) {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+
+	r := make([]float64, 0, len(series))
+
+	var last float64
+	var seen bool
+	for i, v := range series {
+		if finite(v) || policy == KeepNonFinite {
+			r = append(r, v)
+			last, seen = v, true
+			continue
+		}
+
+		switch policy {
+		case ClampNonFinite:
+			if !math.IsNaN(v) && lo <= hi {
+				r = append(r, min(max(v, lo), hi))
+			}
+		case CarryLastNonFinite:
+			if seen {
+				r = append(r, last)
+			}
+		case RejectNonFinite:
+			return nil, fmt.Errorf("%w: %v at index %d", ErrNonFinite, v, i)
+		}
+	}
+
+	return r, nil
+}
+
+// nonFinite handles a non-finite item pushed to the stream according to its
+// NonFinite policy, and returns false if it should be dropped
+func (s *Stream) nonFinite(item float64) (float64, bool) {
+	switch s.NonFinite {
+	case KeepNonFinite:
+		return item, true
+
+	case ClampNonFinite:
+		if math.IsNaN(item) || !s.finiteSeen {
+			return 0, false
+		}
+		return min(max(item, s.finiteLo), s.finiteHi), true
+
+	case CarryLastNonFinite:
+		return s.lastFinite, s.finiteSeen
+
+	case RejectNonFinite:
+		if s.err == nil {
+			s.err = fmt.Errorf("%w: %v at item %d", ErrNonFinite, item, s.pushed)
+		}
+	}
+
+	return 0, false
+}
+
+// Err returns the error for the first value rejected by the stream's
+// NonFinite policy, or nil
+func (s *Stream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
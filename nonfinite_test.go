@@ -0,0 +1,82 @@
+package change
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCleanNonFinite(t *testing.T) {
+
+	nan, inf := math.NaN(), math.Inf(1)
+	series := []float64{inf, 1, nan, 5, -inf, 3, inf}
+
+	var tests = []struct {
+		policy NonFinitePolicy
+		want   []float64
+	}{
+		{SkipNonFinite, []float64{1, 5, 3}},
+		{ClampNonFinite, []float64{5, 1, 5, 1, 3, 5}},
+		{CarryLastNonFinite, []float64{1, 1, 5, 5, 3, 3}},
+	}
+
+	for _, tt := range tests {
+		got, err := CleanNonFinite(series, tt.policy)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("CleanNonFinite(%v)=%v, %v, wanted %v", tt.policy, got, err, tt.want)
+		}
+	}
+
+	if got, _ := CleanNonFinite(series, KeepNonFinite); len(got) != len(series) || !math.IsNaN(got[2]) {
+		t.Errorf("CleanNonFinite(KeepNonFinite)=%v, wanted the series unchanged", got)
+	}
+
+	if _, err := CleanNonFinite(series, RejectNonFinite); !errors.Is(err, ErrNonFinite) {
+		t.Errorf("CleanNonFinite(RejectNonFinite) error=%v, wanted ErrNonFinite", err)
+	}
+}
+
+func TestStreamNonFinite(t *testing.T) {
+
+	for _, policy := range []NonFinitePolicy{SkipNonFinite, ClampNonFinite, CarryLastNonFinite, RejectNonFinite} {
+		s := NewStream(40, 10, 5, 0.999)
+		s.NonFinite = policy
+
+		var found []int
+		for i := 0; i < 100; i++ {
+			v := float64(i % 3)
+			if i >= 60 {
+				v += 10
+			}
+			if i%7 == 0 {
+				v = math.Inf(1)
+			}
+			if i%11 == 0 {
+				v = math.NaN()
+			}
+
+			if cp := s.Push(v); cp != nil {
+				if math.IsNaN(cp.Difference) || math.IsNaN(cp.Confidence) {
+					t.Errorf("%v: change point %+v has NaN statistics", policy, cp)
+				}
+				found = append(found, cp.Offset)
+			}
+		}
+
+		for _, v := range s.Window() {
+			if !finite(v) {
+				t.Errorf("%v: window holds %v", policy, v)
+				break
+			}
+		}
+
+		if len(found) == 0 {
+			t.Errorf("%v: no change found", policy)
+		}
+
+		if err := s.Err(); (err != nil) != (policy == RejectNonFinite) || (err != nil && !errors.Is(err, ErrNonFinite)) {
+			t.Errorf("%v: Err=%v", policy, err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package offline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dgryski/go-change"
+)
+
+// ChangeError is returned by AssertNoChange when series changed
+type ChangeError struct {
+	// Changes are the change points found, in order of index
+	Changes []change.ChangePoint
+}
+
+func (e *ChangeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "offline: %d change", len(e.Changes))
+	if len(e.Changes) != 1 {
+		b.WriteString("s")
+	}
+	b.WriteString(" found:")
+
+	for i, cp := range e.Changes {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, " at %d from %.4g to %.4g (%+.4g", cp.Index, cp.Before.Mean(), cp.After.Mean(), cp.Difference)
+		if m := cp.Before.Mean(); m != 0 {
+			fmt.Fprintf(&b, ", %+.1f%%", 100*cp.Difference/m)
+		}
+		fmt.Fprintf(&b, ", confidence %.4f)", cp.Confidence)
+	}
+
+	return b.String()
+}
+
+// AssertNoChange returns a *ChangeError describing every change found in
+// series by Changes, or nil if there are none.  It is intended for
+// performance regression tests, where a series of benchmark results across
+// commits should stay flat.
+func AssertNoChange(series []float64, d *change.Detector) error {
+	var changes []change.ChangePoint
+	for cp := range Changes(series, d) {
+		changes = append(changes, cp)
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return &ChangeError{Changes: changes}
+}
@@ -0,0 +1,44 @@
+package offline
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestAssertNoChange(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	var flat, step []float64
+	for i := 0; i < 200; i++ {
+		v := 100 + rnd.Float64()
+		flat = append(flat, v)
+		if i >= 150 {
+			v += 10
+		}
+		step = append(step, v)
+	}
+
+	d := &change.Detector{MinSampleSize: 20, MinConfidence: 0.999}
+
+	if err := AssertNoChange(flat, d); err != nil {
+		t.Errorf("AssertNoChange(flat)=%v, wanted nil", err)
+	}
+
+	err := AssertNoChange(step, d)
+
+	var ce *ChangeError
+	if !errors.As(err, &ce) || len(ce.Changes) != 1 || ce.Changes[0].Index != 150 {
+		t.Fatalf("AssertNoChange(step)=%v, wanted one change at 150", err)
+	}
+
+	for _, want := range []string{"1 change found", "at 150", "from 100.5 to 110.4", "+9.9%"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("AssertNoChange error %q does not contain %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package offline
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/dgryski/go-change"
+)
+
+// CheckAll finds the changes in each of many series, as Changes does, using
+// a pool of workers goroutines shared by all the series.  If workers is zero
+// or negative, GOMAXPROCS workers are used.  The detector is shared and must
+// not be modified during the call.  If ctx is cancelled before every series
+// has been searched, CheckAll returns ctx.Err() and no results; once they
+// all have been, the results are returned even if ctx is then cancelled.
+func CheckAll(ctx context.Context, series map[string][]float64, d *change.Detector, workers int) (map[string][]change.ChangePoint, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	type result struct {
+		name    string
+		changes []change.ChangePoint
+	}
+
+	names := make(chan string)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				changes := slices.Collect(Changes(series[name], d))
+				select {
+				case results <- result{name, changes}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(names)
+		for name := range series {
+			select {
+			case names <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	all := make(map[string][]change.ChangePoint, len(series))
+	for r := range results {
+		all[r.name] = r.changes
+	}
+
+	// a cancellation after the last series was searched skipped nothing
+	if len(all) < len(series) {
+		return nil, ctx.Err()
+	}
+
+	return all, nil
+}
@@ -0,0 +1,70 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestCheckAll(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	// series i has a change at 100+i, or none if i is a multiple of 10
+	series := make(map[string][]float64)
+	for i := 0; i < 50; i++ {
+		var s []float64
+		for j := 0; j < 300; j++ {
+			v := rnd.Float64()
+			if i%10 != 0 && j >= 100+i {
+				v += 5
+			}
+			s = append(s, v)
+		}
+		series[fmt.Sprintf("s%d", i)] = s
+	}
+
+	d := &change.Detector{MinSampleSize: 30, MinConfidence: 0.9999}
+
+	all, err := CheckAll(context.Background(), series, d, 4)
+	if err != nil {
+		t.Fatalf("CheckAll failed: %v", err)
+	}
+
+	if len(all) != len(series) {
+		t.Errorf("CheckAll returned %d series, wanted %d", len(all), len(series))
+	}
+
+	for i := 0; i < 50; i++ {
+		changes := all[fmt.Sprintf("s%d", i)]
+		if i%10 == 0 {
+			if len(changes) != 0 {
+				t.Errorf("series %d changes=%v, wanted none", i, changes)
+			}
+			continue
+		}
+		if len(changes) != 1 || changes[0].Index != 100+i {
+			t.Errorf("series %d changes=%v, wanted one at %d", i, changes, 100+i)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CheckAll(ctx, series, d, 4); err != context.Canceled {
+		t.Errorf("CheckAll with cancelled context err=%v, wanted context.Canceled", err)
+	}
+
+	// a context cancelled too late to skip any series
+	if all, err := CheckAll(lateCancel{context.Background()}, series, d, 4); err != nil || len(all) != len(series) {
+		t.Errorf("CheckAll cancelled after finishing returned %d series, err=%v, wanted all of them", len(all), err)
+	}
+}
+
+// lateCancel is a context reporting a cancellation that is never signalled on
+// Done, as if it happened after the work was finished
+type lateCancel struct{ context.Context }
+
+func (lateCancel) Err() error { return context.Canceled }
@@ -0,0 +1,40 @@
+package offline
+
+import (
+	"iter"
+
+	"github.com/dgryski/go-change"
+)
+
+// Changes returns an iterator over all the changes in the level of series,
+// found by binary segmentation with d as for Poisson.  Change points are
+// yielded in order of index, and segments after the point at which the
+// caller stops ranging are not searched.
+func Changes(series []float64, d *change.Detector) iter.Seq[change.ChangePoint] {
+	return func(yield func(change.ChangePoint) bool) {
+		segmentChanges(series, 0, len(series), d, yield)
+	}
+}
+
+// segmentChanges searches series[start:end], returning false once yield does
+func segmentChanges(series []float64, start, end int, d *change.Detector, yield func(change.ChangePoint) bool) bool {
+	cp := d.Check(series[start:end])
+	if cp == nil {
+		return true
+	}
+
+	idx := start + cp.Index
+
+	if !segmentChanges(series, start, idx, d, yield) {
+		return false
+	}
+
+	cp.Index, cp.Offset, cp.Lag = idx, idx, len(series)-idx
+	cp.IndexLow += start
+	cp.IndexHigh += start
+	if !yield(*cp) {
+		return false
+	}
+
+	return segmentChanges(series, idx, end, d, yield)
+}
@@ -0,0 +1,72 @@
+package offline
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/datasets"
+)
+
+func TestChanges(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	var series []float64
+	for i := 0; i < 600; i++ {
+		v := rnd.Float64()
+		switch {
+		case i >= 400:
+			v += 1
+		case i >= 200:
+			v += 5
+		}
+		series = append(series, v)
+	}
+
+	d := &change.Detector{MinSampleSize: 30, MinConfidence: 0.9999}
+
+	var got []int
+	for cp := range Changes(series, d) {
+		got = append(got, cp.Offset)
+		if cp.IndexLow > cp.Index || cp.IndexHigh < cp.Index {
+			t.Errorf("change at %d has interval [%d, %d]", cp.Index, cp.IndexLow, cp.IndexHigh)
+		}
+	}
+
+	if len(got) != 2 || got[0] != 200 || got[1] != 400 {
+		t.Errorf("Changes=%v, wanted [200 400]", got)
+	}
+
+	// stopping early yields only the first change
+	var first []int
+	for cp := range Changes(series, d) {
+		first = append(first, cp.Offset)
+		break
+	}
+
+	if len(first) != 1 || first[0] != 200 {
+		t.Errorf("Changes with break=%v, wanted [200]", first)
+	}
+}
+
+func TestChangesDatasets(t *testing.T) {
+
+	d := &change.Detector{MinSampleSize: 30, MinConfidence: 0.9999}
+
+	for _, ds := range append(datasets.Synthetic(), datasets.Nile()) {
+		var found []int
+		for cp := range Changes(ds.Values, d) {
+			found = append(found, cp.Index)
+		}
+
+		ok := len(found) == len(ds.Changes)
+		for i := 0; ok && i < len(found); i++ {
+			ok = math.Abs(float64(found[i]-ds.Changes[i])) <= 25
+		}
+		if !ok {
+			t.Errorf("%s: Changes=%v, wanted %v", ds.Name, found, ds.Changes)
+		}
+	}
+}
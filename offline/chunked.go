@@ -0,0 +1,61 @@
+package offline
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgryski/go-change"
+)
+
+// ErrInvalidChunk is returned by CheckChunked when the chunks would not advance
+var ErrInvalidChunk = errors.New("offline: invalid chunk size")
+
+// CheckChunked finds the changes in the level of series as Changes does, but
+// searches overlapping chunks of at most chunk items, so the memory used by
+// the search is bounded by the chunk size rather than the length of the
+// series.  Consecutive chunks share overlap items; each change point is
+// taken from the chunk in which it is furthest from the edges, so changes in
+// an overlap are reported once.  The overlap should be at least twice the
+// detector's MinSampleSize so that a change near a chunk boundary is seen
+// with enough items on both sides.  Change points are returned in order of
+// index.
+func CheckChunked(series []float64, chunk, overlap int, d *change.Detector) ([]change.ChangePoint, error) {
+	if overlap < 0 || chunk <= overlap {
+		return nil, fmt.Errorf("%w: chunk %d must be larger than overlap %d", ErrInvalidChunk, chunk, overlap)
+	}
+
+	var changes []change.ChangePoint
+
+	step := chunk - overlap
+	for start := 0; ; start += step {
+		end := min(start+chunk, len(series))
+
+		// the core of each chunk extends halfway into the overlaps with
+		// its neighbours
+		lo, hi := start+overlap/2, end-(overlap-overlap/2)
+		if start == 0 {
+			lo = 0
+		}
+		if end == len(series) {
+			hi = end
+		}
+
+		for cp := range Changes(series[start:end], d) {
+			idx := start + cp.Index
+			if idx < lo || idx >= hi {
+				continue
+			}
+
+			cp.Index, cp.Offset, cp.Lag = idx, idx, len(series)-idx
+			cp.IndexLow += start
+			cp.IndexHigh += start
+			changes = append(changes, cp)
+		}
+
+		if end == len(series) {
+			break
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,63 @@
+package offline
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestCheckChunked(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	// a level change every 250 items, alternating up and down
+	var series []float64
+	for i := 0; i < 2000; i++ {
+		v := rnd.Float64()
+		if (i/250)%2 == 1 {
+			v += 5
+		}
+		series = append(series, v)
+	}
+
+	d := &change.Detector{MinSampleSize: 30, MinConfidence: 0.9999}
+
+	var tests = []struct {
+		chunk, overlap int
+	}{
+		{500, 100},
+		{300, 120},
+		{2000, 0},
+		{5000, 100},
+	}
+
+	for _, tt := range tests {
+		changes, err := CheckChunked(series, tt.chunk, tt.overlap, d)
+		if err != nil {
+			t.Errorf("CheckChunked(%d, %d) failed: %v", tt.chunk, tt.overlap, err)
+			continue
+		}
+
+		var got []int
+		for _, cp := range changes {
+			got = append(got, cp.Index)
+		}
+
+		if len(got) != 7 {
+			t.Errorf("CheckChunked(%d, %d)=%v, wanted 7 changes", tt.chunk, tt.overlap, got)
+			continue
+		}
+
+		for i, idx := range got {
+			if want := 250 * (i + 1); idx != want {
+				t.Errorf("CheckChunked(%d, %d) change %d at %d, wanted %d", tt.chunk, tt.overlap, i, idx, want)
+			}
+		}
+	}
+
+	if _, err := CheckChunked(series, 100, 100, d); !errors.Is(err, ErrInvalidChunk) {
+		t.Errorf("CheckChunked with overlap equal to chunk err=%v, wanted ErrInvalidChunk", err)
+	}
+}
@@ -0,0 +1,44 @@
+package offline_test
+
+import (
+	"fmt"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/datasets"
+	"github.com/dgryski/go-change/offline"
+)
+
+func ExampleChanges() {
+	ds := datasets.Synthetic()[4] // two steps, at 200 and 400
+
+	d := &change.Detector{MinSampleSize: 30, MinConfidence: 0.9999}
+	for cp := range offline.Changes(ds.Values, d) {
+		fmt.Println("change near", cp.Index/10*10)
+	}
+	// Output:
+	// change near 200
+	// change near 400
+}
+
+func ExampleSegmentK() {
+	nile := datasets.Nile()
+
+	changes, err := offline.SegmentK(nile.Values, 1)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	segments, err := offline.FitSegments(nile.Values, changes)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, s := range segments {
+		fmt.Printf("[%d, %d)\n", s.Start, s.End)
+	}
+	// Output:
+	// [0, 28)
+	// [28, 100)
+}
@@ -0,0 +1,124 @@
+// Package offline analyses complete series for change points
+/*
+Where the change package is concerned with finding a change in a window of
+recent data, this package works with an entire recorded series at once:
+segmenting it, fitting models to the segments, and reporting on them.
+
+The analysis is sequential and deterministic: change points and segments are
+always returned in order of index, so results can be compared directly.
+
+NaN and infinite values make the statistics of any segment holding them NaN.
+Series that may contain them should first be cleaned with
+change.CleanNonFinite.
+*/
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrSeriesTooShort is returned when a series has too few items for the
+	// requested number of change points
+	ErrSeriesTooShort = errors.New("offline: series too short")
+
+	// ErrChangeOutOfRange is returned when a change point index does not
+	// split the series
+	ErrChangeOutOfRange = errors.New("offline: change point out of range")
+)
+
+// Segment is a linear model fit to the part of a series between two change points
+type Segment struct {
+	// Start and End are the indices of the first item of the segment and one past its last item
+	Start, End int
+
+	// Intercept and Slope describe the fitted line y = Intercept + Slope*(i-Start)
+	Intercept float64
+	Slope     float64
+
+	// RSS is the residual sum of squares of the fit
+	RSS float64
+}
+
+// Len returns the number of items in the segment
+func (s *Segment) Len() int { return s.End - s.Start }
+
+// At returns the fitted value at index i
+func (s *Segment) At(i int) float64 { return s.Intercept + s.Slope*float64(i-s.Start) }
+
+// FitSegments splits series at the given change point indices and fits a
+// line to each segment by least squares.  Each change point is the index of
+// the first item of a new segment.
+func FitSegments(series []float64, changes []int) ([]Segment, error) {
+
+	bounds := append([]int(nil), changes...)
+	sort.Ints(bounds)
+
+	for _, c := range bounds {
+		if c <= 0 || c >= len(series) {
+			return nil, fmt.Errorf("%w: %d for series of length %d", ErrChangeOutOfRange, c, len(series))
+		}
+	}
+
+	bounds = append(bounds, len(series))
+
+	segments := make([]Segment, 0, len(bounds))
+
+	start := 0
+	for _, end := range bounds {
+		if end == start {
+			// duplicate change point
+			continue
+		}
+		segments = append(segments, fitLine(series, start, end))
+		start = end
+	}
+
+	return segments, nil
+}
+
+// Reconstruct returns the fitted values of the segments, one per item of the original series
+func Reconstruct(segments []Segment) []float64 {
+	if len(segments) == 0 {
+		return nil
+	}
+
+	fitted := make([]float64, segments[len(segments)-1].End)
+	for i := range segments {
+		s := &segments[i]
+		for j := s.Start; j < s.End; j++ {
+			fitted[j] = s.At(j)
+		}
+	}
+
+	return fitted
+}
+
+// fitLine fits a line to series[start:end]
+func fitLine(series []float64, start, end int) Segment {
+	var sx, sy, sxx, sxy float64
+	for i, y := range series[start:end] {
+		x := float64(i)
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+
+	n := float64(end - start)
+	s := Segment{Start: start, End: end}
+
+	if vx := sxx - sx*sx/n; vx > 0 {
+		s.Slope = (sxy - sx*sy/n) / vx
+	}
+	s.Intercept = (sy - s.Slope*sx) / n
+
+	for i := start; i < end; i++ {
+		r := series[i] - s.At(i)
+		s.RSS += r * r
+	}
+
+	return s
+}
@@ -0,0 +1,55 @@
+package offline
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitSegments(t *testing.T) {
+
+	var series []float64
+	for i := 0; i < 30; i++ {
+		switch {
+		case i < 10:
+			series = append(series, 5)
+		case i < 20:
+			series = append(series, 1+2*float64(i-10))
+		default:
+			series = append(series, 100-float64(i-20))
+		}
+	}
+
+	segments, err := FitSegments(series, []int{20, 10})
+	if err != nil {
+		t.Fatalf("FitSegments failed: %v", err)
+	}
+
+	want := []Segment{
+		{Start: 0, End: 10, Intercept: 5, Slope: 0},
+		{Start: 10, End: 20, Intercept: 1, Slope: 2},
+		{Start: 20, End: 30, Intercept: 100, Slope: -1},
+	}
+
+	if len(segments) != len(want) {
+		t.Fatalf("FitSegments returned %d segments, wanted %d", len(segments), len(want))
+	}
+
+	for i, s := range segments {
+		w := want[i]
+		if s.Start != w.Start || s.End != w.End || math.Abs(s.Intercept-w.Intercept) > 1e-9 || math.Abs(s.Slope-w.Slope) > 1e-9 || s.RSS > 1e-9 {
+			t.Errorf("segment %d=%+v, wanted %+v", i, s, w)
+		}
+	}
+
+	fitted := Reconstruct(segments)
+	for i := range series {
+		if math.Abs(fitted[i]-series[i]) > 1e-9 {
+			t.Errorf("Reconstruct[%d]=%f, wanted %f", i, fitted[i], series[i])
+		}
+	}
+
+	if _, err := FitSegments(series, []int{30}); !errors.Is(err, ErrChangeOutOfRange) {
+		t.Errorf("FitSegments with out of range change point err=%v, wanted ErrChangeOutOfRange", err)
+	}
+}
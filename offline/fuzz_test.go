@@ -0,0 +1,63 @@
+package offline
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+// fuzzFloats decodes data as little-endian float64s, so the fuzzer can
+// produce NaNs, infinities and denormals
+func fuzzFloats(data []byte) []float64 {
+	r := make([]float64, len(data)/8)
+	for i := range r {
+		r[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	return r
+}
+
+func FuzzChanges(f *testing.F) {
+	f.Add([]byte{}, 0, 0)
+	f.Add(make([]byte, 8), 1, 1)
+	f.Add(make([]byte, 8*40), 2, 3)
+	f.Add([]byte{}, -32, 0)
+
+	nan := make([]byte, 8*6)
+	for i := range nan {
+		nan[i] = 0xff
+	}
+	f.Add(nan, 0, 5)
+
+	f.Fuzz(func(t *testing.T, data []byte, minSample, k int) {
+		// SegmentK is quadratic in the length of the series
+		if len(data) > 8*1024 {
+			return
+		}
+		series := fuzzFloats(data)
+		d := &change.Detector{MinSampleSize: minSample % 64}
+
+		for cp := range Changes(series, d) {
+			if cp.Index <= 0 || cp.Index >= len(series) {
+				t.Errorf("Changes: index %d out of range for %d items", cp.Index, len(series))
+			}
+		}
+
+		if changes, err := SegmentK(series, k%8); err == nil {
+			if _, err := FitSegments(series, changes); err != nil {
+				t.Errorf("FitSegments(SegmentK)=%v", err)
+			}
+		}
+
+		CheckChunked(series, 1+abs(k)%128, abs(minSample)%32, d)
+		SegmentAuto(series, abs(k)%8, BIC)
+	})
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
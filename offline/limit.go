@@ -0,0 +1,42 @@
+package offline
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dgryski/go-change"
+)
+
+// Limit returns at most k of changes, keeping those with the highest Score,
+// in order of index.  It panics if k is negative.
+//
+// Limit is meant for the changes found in one series by Changes or
+// CheckChunked.  To get a bounded number of change points regardless of the
+// detection threshold, pass Changes a Detector with a MinConfidence of 0, so
+// every split with any difference in means is reported, and then apply
+// Limit.  Because the change points all come from the same segmentation they
+// still respect its minimum segment length.
+//
+// Each Score is the between-class scatter of splitting the segment the change
+// was found in, which grows with the length of that segment, so scores from
+// segments of different lengths are not strictly comparable: Limit prefers
+// large shifts splitting long segments, which are not always the most
+// significant changes.  Scores from different series or detectors, or from
+// Poisson, should not be mixed.
+func Limit(changes []change.ChangePoint, k int) []change.ChangePoint {
+	if k < 0 {
+		panic(fmt.Sprintf("offline: invalid limit %d", k))
+	}
+
+	if len(changes) <= k {
+		return changes
+	}
+
+	r := append([]change.ChangePoint(nil), changes...)
+
+	sort.SliceStable(r, func(i, j int) bool { return r[i].Score > r[j].Score })
+	r = r[:k]
+	sort.SliceStable(r, func(i, j int) bool { return r[i].Index < r[j].Index })
+
+	return r
+}
@@ -0,0 +1,42 @@
+package offline
+
+import (
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestLimit(t *testing.T) {
+
+	changes := []change.ChangePoint{
+		{Index: 10, Score: 5},
+		{Index: 20, Score: 1},
+		{Index: 30, Score: 9},
+		{Index: 40, Score: 3},
+	}
+
+	r := Limit(changes, 2)
+
+	if len(r) != 2 || r[0].Index != 10 || r[1].Index != 30 {
+		t.Errorf("Limit(2)=%v, wanted indices 10 and 30", r)
+	}
+
+	if r := Limit(changes, 10); len(r) != 4 {
+		t.Errorf("Limit(10) returned %d changes, wanted 4", len(r))
+	}
+
+	if changes[0].Index != 10 || changes[2].Index != 30 {
+		t.Errorf("Limit modified its argument")
+	}
+
+	if r := Limit(changes, 0); len(r) != 0 {
+		t.Errorf("Limit(0)=%v, wanted none", r)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Limit(-1) did not panic")
+		}
+	}()
+	Limit(changes, -1)
+}
@@ -0,0 +1,35 @@
+package offline
+
+import "github.com/dgryski/go-change"
+
+// Poisson finds all the changes in the rate of a series of event counts, such
+// as errors per minute, by binary segmentation: the strongest change is found
+// with d, and each side of it is searched again until no more changes are
+// found.  The detector's MinSampleSize is the minimum segment length; it is
+// enforced while searching, so no two change points are closer together than
+// that.  The Score of each change point is its Poisson likelihood ratio.
+// Change points are returned in order of index.
+func Poisson(counts []float64, d *change.RateDetector) []change.ChangePoint {
+	var changes []change.ChangePoint
+	segmentPoisson(counts, 0, len(counts), d, &changes)
+	return changes
+}
+
+// segmentPoisson searches counts[start:end]
+func segmentPoisson(counts []float64, start, end int, d *change.RateDetector, changes *[]change.ChangePoint) {
+	cp := d.Check(counts[start:end], nil)
+	if cp == nil {
+		return
+	}
+
+	idx := start + cp.Index
+
+	segmentPoisson(counts, start, idx, d, changes)
+
+	cp.Index, cp.Offset, cp.Lag = idx, idx, len(counts)-idx
+	cp.IndexLow += start
+	cp.IndexHigh += start
+	*changes = append(*changes, *cp)
+
+	segmentPoisson(counts, idx, end, d, changes)
+}
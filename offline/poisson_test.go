@@ -0,0 +1,63 @@
+package offline
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestPoisson(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	rates := []struct {
+		start int
+		rate  float64
+	}{{0, 2}, {300, 8}, {600, 3}}
+
+	var counts []float64
+	for i := 0; i < 900; i++ {
+		rate := rates[0].rate
+		for _, r := range rates {
+			if i >= r.start {
+				rate = r.rate
+			}
+		}
+		counts = append(counts, poisson(rnd, rate))
+	}
+
+	changes := Poisson(counts, &change.RateDetector{MinSampleSize: 30, MinConfidence: 0.9999})
+
+	// a minimum segment length longer than the data between changes must suppress them
+	if long := Poisson(counts, &change.RateDetector{MinSampleSize: 400, MinConfidence: 0.9999}); len(long) > 1 {
+		t.Errorf("Poisson with MinSampleSize 400 found %d changes: %v", len(long), long)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Poisson found %d changes, wanted 2: %v", len(changes), changes)
+	}
+
+	for i, want := range []int{300, 600} {
+		cp := changes[i]
+		if cp.Offset < want-10 || cp.Offset > want+10 {
+			t.Errorf("change %d at %d, wanted about %d", i, cp.Offset, want)
+		}
+		if cp.Score <= 0 {
+			t.Errorf("change %d has score %f", i, cp.Score)
+		}
+		if cp.IndexLow > cp.Index || cp.IndexHigh < cp.Index {
+			t.Errorf("change %d at %d has interval [%d, %d]", i, cp.Index, cp.IndexLow, cp.IndexHigh)
+		}
+	}
+}
+
+// poisson returns a Poisson distributed count with the given mean, using Knuth's algorithm
+func poisson(rnd *rand.Rand, mean float64) float64 {
+	var k float64
+	for p := rnd.Float64(); p > math.Exp(-mean); p *= rnd.Float64() {
+		k++
+	}
+	return k
+}
@@ -0,0 +1,186 @@
+package offline
+
+import (
+	"fmt"
+	"math"
+)
+
+// SegmentK returns the k change points that split series into the k+1
+// segments with the smallest total within-segment sum of squared deviations
+// from the segment means.  The segmentation is exact, found by dynamic
+// programming in O(k*n^2) time.  Change points are the indices of the first
+// item of each new segment, in increasing order.
+func SegmentK(series []float64, k int) ([]int, error) {
+	if k < 0 || k >= len(series) {
+		return nil, fmt.Errorf("%w: cannot place %d change points in a series of length %d", ErrSeriesTooShort, k, len(series))
+	}
+
+	dp := newSegmentation(series, k)
+	return dp.changes(k), nil
+}
+
+// segmentation holds the dynamic programming tables for segmenting a series
+// into up to maxK+1 segments
+type segmentation struct {
+	n int
+
+	// prefix sums of the series and its squares
+	sum, sumsq []float64
+
+	// cost[j][i] is the smallest cost of splitting series[:i] into j+1 segments
+	cost [][]float64
+
+	// split[j][i] is the start of the last segment in that split
+	split [][]int
+}
+
+func newSegmentation(series []float64, maxK int) *segmentation {
+	n := len(series)
+
+	s := &segmentation{
+		n:     n,
+		sum:   make([]float64, n+1),
+		sumsq: make([]float64, n+1),
+		cost:  make([][]float64, maxK+1),
+		split: make([][]int, maxK+1),
+	}
+
+	for i, v := range series {
+		s.sum[i+1] = s.sum[i] + v
+		s.sumsq[i+1] = s.sumsq[i] + v*v
+	}
+
+	for j := 0; j <= maxK; j++ {
+		s.cost[j] = make([]float64, n+1)
+		s.split[j] = make([]int, n+1)
+
+		for i := 0; i <= n; i++ {
+			if j == 0 {
+				s.cost[0][i] = s.sse(0, i)
+				continue
+			}
+
+			best, bestAt := math.Inf(1), j
+			// the last segment is series[t:i]; series[:t] holds the other j segments
+			for t := j; t < i; t++ {
+				if c := s.cost[j-1][t] + s.sse(t, i); c < best {
+					best, bestAt = c, t
+				}
+			}
+			s.cost[j][i], s.split[j][i] = best, bestAt
+		}
+	}
+
+	return s
+}
+
+// sse returns the sum of squared deviations from the mean of series[from:to]
+func (s *segmentation) sse(from, to int) float64 {
+	if to <= from {
+		return 0
+	}
+	n := float64(to - from)
+	sum := s.sum[to] - s.sum[from]
+	v := (s.sumsq[to] - s.sumsq[from]) - sum*sum/n
+	if v < 0 {
+		// rounding error
+		v = 0
+	}
+	return v
+}
+
+// total returns the cost of the best segmentation with k change points
+func (s *segmentation) total(k int) float64 { return s.cost[k][s.n] }
+
+// changes returns the change points of the best segmentation with k change points
+func (s *segmentation) changes(k int) []int {
+	changes := make([]int, k)
+	i := s.n
+	for j := k; j > 0; j-- {
+		i = s.split[j][i]
+		changes[j-1] = i
+	}
+	return changes
+}
+
+// Criterion selects the number of change points for SegmentAuto
+type Criterion int
+
+const (
+	// BIC minimizes the Bayesian information criterion
+	BIC Criterion = iota
+
+	// AIC minimizes the Akaike information criterion, which penalizes extra change points less than BIC
+	AIC
+
+	// Elbow picks the point of the cost curve furthest from the line joining its ends
+	Elbow
+)
+
+// SegmentAuto is like SegmentK, but chooses the number of change points, up to
+// maxK, using the given criterion.  The information criteria assume a
+// Gaussian model with a shared variance and a separate mean for each segment.
+func SegmentAuto(series []float64, maxK int, criterion Criterion) ([]int, error) {
+	if maxK >= len(series) {
+		maxK = len(series) - 1
+	}
+	if maxK < 0 {
+		return nil, fmt.Errorf("%w: cannot segment a series of length %d", ErrSeriesTooShort, len(series))
+	}
+
+	dp := newSegmentation(series, maxK)
+	return dp.changes(dp.choose(maxK, criterion)), nil
+}
+
+// choose returns the number of change points selected by the criterion
+func (s *segmentation) choose(maxK int, criterion Criterion) int {
+
+	if criterion == Elbow {
+		return s.elbow(maxK)
+	}
+
+	n := float64(s.n)
+
+	penalty := math.Log(n)
+	if criterion == AIC {
+		penalty = 2
+	}
+
+	best, bestK := math.Inf(1), 0
+	for k := 0; k <= maxK; k++ {
+		sse := s.total(k)
+		if sse <= 0 {
+			// a perfect fit; more change points cannot improve on it
+			return k
+		}
+
+		// k+1 means, k locations and the variance
+		params := float64(2*k + 2)
+		if ic := n*math.Log(sse/n) + penalty*params; ic < best {
+			best, bestK = ic, k
+		}
+	}
+
+	return bestK
+}
+
+// elbow returns the number of change points at the point of the cost curve
+// furthest below the straight line from no change points to maxK
+func (s *segmentation) elbow(maxK int) int {
+	if maxK < 2 {
+		return maxK
+	}
+
+	first, last := s.total(0), s.total(maxK)
+
+	var best float64
+	var bestK int
+	for k := 1; k < maxK; k++ {
+		line := first + (last-first)*float64(k)/float64(maxK)
+		if d := line - s.total(k); d > best {
+			best, bestK = d, k
+		}
+	}
+
+	return bestK
+}
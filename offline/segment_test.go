@@ -0,0 +1,92 @@
+package offline
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSegmentK(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	// three test phases with different means
+	var series []float64
+	for i := 0; i < 150; i++ {
+		level := 1.0
+		switch {
+		case i >= 100:
+			level = 3
+		case i >= 40:
+			level = 5
+		}
+		series = append(series, level+0.3*rnd.NormFloat64())
+	}
+
+	changes, err := SegmentK(series, 2)
+	if err != nil {
+		t.Fatalf("SegmentK failed: %v", err)
+	}
+
+	if want := []int{40, 100}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("SegmentK=%v, wanted %v", changes, want)
+	}
+
+	if changes, _ := SegmentK(series, 0); len(changes) != 0 {
+		t.Errorf("SegmentK(0)=%v, wanted none", changes)
+	}
+
+	if _, err := SegmentK(series[:3], 3); !errors.Is(err, ErrSeriesTooShort) {
+		t.Errorf("SegmentK with too many change points err=%v, wanted ErrSeriesTooShort", err)
+	}
+
+	if _, err := SegmentAuto(nil, 3, BIC); !errors.Is(err, ErrSeriesTooShort) {
+		t.Errorf("SegmentAuto on empty series err=%v, wanted ErrSeriesTooShort", err)
+	}
+}
+
+func TestSegmentAuto(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(2))
+
+	var series []float64
+	for i := 0; i < 200; i++ {
+		level := 0.0
+		switch {
+		case i >= 150:
+			level = 5
+		case i >= 90:
+			level = 0
+		case i >= 30:
+			level = 5
+		}
+		series = append(series, level+0.5*rnd.NormFloat64())
+	}
+
+	want := []int{30, 90, 150}
+
+	for _, c := range []Criterion{BIC, AIC, Elbow} {
+		changes, err := SegmentAuto(series, 8, c)
+		if err != nil {
+			t.Fatalf("SegmentAuto(%d) failed: %v", c, err)
+		}
+
+		if c != AIC && !reflect.DeepEqual(changes, want) {
+			t.Errorf("SegmentAuto(%d)=%v, wanted %v", c, changes, want)
+		}
+
+		if c == AIC && len(changes) < len(want) {
+			t.Errorf("SegmentAuto(AIC)=%v, wanted at least %d changes", changes, len(want))
+		}
+	}
+
+	var flat []float64
+	for i := 0; i < 100; i++ {
+		flat = append(flat, rnd.NormFloat64())
+	}
+
+	if changes, _ := SegmentAuto(flat, 5, BIC); len(changes) != 0 {
+		t.Errorf("SegmentAuto(BIC) on noise=%v, wanted no changes", changes)
+	}
+}
@@ -0,0 +1,28 @@
+package offline
+
+// Smooth returns the centered moving average of series over width items.
+// Each item is averaged with the width/2 items on either side of it, so a
+// step stays at its index, unlike the trailing average of
+// change.Stream.Smooth, which needs no later items but delays the step.
+// Near the ends the average is over the items that exist, without padding.
+// A width of 1 or less returns a copy of series.
+func Smooth(series []float64, width int) []float64 {
+	r := make([]float64, len(series))
+	if width <= 1 {
+		copy(r, series)
+		return r
+	}
+
+	cum := make([]float64, len(series)+1)
+	for i, v := range series {
+		cum[i+1] = cum[i] + v
+	}
+
+	h := width / 2
+	for i := range r {
+		lo, hi := max(0, i-h), min(len(series), i+h+1)
+		r[i] = (cum[hi] - cum[lo]) / float64(hi-lo)
+	}
+
+	return r
+}
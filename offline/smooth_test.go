@@ -0,0 +1,35 @@
+package offline
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSmooth(t *testing.T) {
+
+	var tests = []struct {
+		series []float64
+		width  int
+		want   []float64
+	}{
+		{nil, 3, []float64{}},
+		{[]float64{1, 2, 3}, 1, []float64{1, 2, 3}},
+		{[]float64{1, 2, 3, 4, 5}, 3, []float64{1.5, 2, 3, 4, 4.5}},
+		{[]float64{0, 0, 0, 6, 6, 6}, 3, []float64{0, 0, 2, 4, 6, 6}},
+		{[]float64{1, 2, 3, 4}, 10, []float64{2.5, 2.5, 2.5, 2.5}},
+	}
+
+	for _, tt := range tests {
+		got := Smooth(tt.series, tt.width)
+		if len(got) != len(tt.want) {
+			t.Errorf("Smooth(%v, %d)=%v, wanted %v", tt.series, tt.width, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+				t.Errorf("Smooth(%v, %d)=%v, wanted %v", tt.series, tt.width, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package offline
+
+import (
+	"math"
+
+	"github.com/dgryski/go-change"
+)
+
+// VerifyChangeAt checks that series changed level within tolerance items of
+// expectedIndex, such as when a feature flag was flipped, by at least
+// minDifference in either direction.  The series is segmented as by Changes,
+// so other changes elsewhere in the series neither hide the expected change
+// nor distort its size.  It returns the largest change found near the
+// expected index, whose EffectSize is the measured effect, or nil if there is
+// none; ok reports whether it is a large enough change.
+func VerifyChangeAt(series []float64, expectedIndex, tolerance int, minDifference float64, d *change.Detector) (cp *change.ChangePoint, ok bool) {
+	for c := range Changes(series, d) {
+		if c.Index < expectedIndex-tolerance || c.Index > expectedIndex+tolerance {
+			continue
+		}
+		if cp == nil || math.Abs(c.Difference) > math.Abs(cp.Difference) {
+			cp = &c
+		}
+	}
+
+	if cp == nil {
+		return nil, false
+	}
+
+	return cp, math.Abs(cp.Difference) >= minDifference
+}
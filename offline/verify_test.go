@@ -0,0 +1,56 @@
+package offline
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestVerifyChangeAt(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	// a small change at 200, and a much larger one at 400
+	var series []float64
+	for i := 0; i < 600; i++ {
+		v := rnd.Float64()
+		if i >= 200 {
+			v += 1
+		}
+		if i >= 400 {
+			v += 10
+		}
+		series = append(series, v)
+	}
+
+	d := &change.Detector{MinSampleSize: 30, MinConfidence: 0.999}
+
+	var tests = []struct {
+		expected, tolerance int
+		minDifference       float64
+		found, ok           bool
+	}{
+		{200, 10, 0.5, true, true},
+		{195, 10, 0.5, true, true},
+		{200, 10, 2, true, false},
+		{300, 10, 0.5, false, false},
+		{400, 5, 5, true, true},
+	}
+
+	for _, tt := range tests {
+		cp, ok := VerifyChangeAt(series, tt.expected, tt.tolerance, tt.minDifference, d)
+		if (cp != nil) != tt.found || ok != tt.ok {
+			t.Errorf("VerifyChangeAt(%d, %d, %f)=%v, %v, wanted found=%v ok=%v", tt.expected, tt.tolerance, tt.minDifference, cp, ok, tt.found, tt.ok)
+			continue
+		}
+
+		if cp != nil && (cp.Index < tt.expected-tt.tolerance || cp.Index > tt.expected+tt.tolerance) {
+			t.Errorf("VerifyChangeAt(%d, %d) found change at %d", tt.expected, tt.tolerance, cp.Index)
+		}
+
+		if cp != nil && cp.EffectSize() <= 0 {
+			t.Errorf("VerifyChangeAt(%d) effect size=%f, wanted positive", tt.expected, cp.EffectSize())
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package change
+
+// SetParams changes the parameters of a running stream without discarding the
+// items in its window, so sensitivity can be tuned without waiting for the
+// window to refill.  The new parameters are validated first and applied
+// together, and SetParams may be called concurrently with Push.  If the
+// window grows, no checks are run until it holds only pushed items again;
+// if it shrinks, the oldest items are dropped.
+func (s *Stream) SetParams(c Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c.BlockSize != s.blockSize {
+		if s.bufidx >= c.BlockSize {
+			s.flush()
+		}
+
+		buffer := make([]float64, c.BlockSize)
+		copy(buffer, s.buffer[:s.bufidx])
+		s.buffer = buffer
+
+		if s.weights != nil {
+			s.wbuffer = resizeWeights(s.wbuffer[:s.bufidx], c.BlockSize)
+		}
+
+		s.blockSize = c.BlockSize
+	}
+
+	if c.WindowSize != s.windowSize {
+		windowed := s.items - s.bufidx
+		valid := s.windowSize
+		if s.readyAt > windowed {
+			valid -= s.readyAt - windowed
+		}
+
+		old, w := s.windowSize, c.WindowSize
+		keep := min(old, w)
+
+		data := make([]float64, w)
+		copy(data[w-keep:], s.data[old-keep:])
+		s.data = data
+
+		if s.weights != nil {
+			weights := resizeWeights(nil, w)
+			copy(weights[w-keep:], s.weights[old-keep:])
+			s.weights = weights
+		}
+
+		s.windowSize = w
+		s.readyAt = windowed + w - min(valid, w)
+	}
+
+	s.detector.MinSampleSize = c.MinSampleSize
+	s.detector.MinConfidence = c.MinConfidence
+
+	return nil
+}
+
+// resizeWeights returns a slice of n weights starting with w, padded with 1s
+func resizeWeights(w []float64, n int) []float64 {
+	r := make([]float64, n)
+	copy(r, w)
+	for i := len(w); i < n; i++ {
+		r[i] = 1
+	}
+	return r
+}
@@ -0,0 +1,52 @@
+package change
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetParams(t *testing.T) {
+
+	s := NewStream(40, 5, 5, 0.95)
+
+	if err := s.SetParams(Config{40, 25, 5, 0.95}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("SetParams with invalid config err=%v, wanted ErrInvalidConfig", err)
+	}
+
+	for i := 0; i < 40; i++ {
+		s.Push(float64(i % 2))
+	}
+
+	// growing the window keeps the 40 items already pushed, so the stream
+	// is ready after another 40
+	if err := s.SetParams(Config{80, 10, 10, 0.99}); err != nil {
+		t.Fatalf("SetParams failed: %v", err)
+	}
+
+	var r *ChangePoint
+	var i int
+	for i = 40; i < 200 && r == nil; i++ {
+		v := float64(i % 2)
+		if i >= 70 {
+			v += 10
+		}
+		r = s.Push(v)
+	}
+
+	if r == nil || i != 80 || r.Offset != 70 {
+		t.Errorf("Stream after SetParams returned %v after %d items, wanted change at 70 after 80", r, i)
+	}
+
+	if w := s.Window(); len(w) != 80 {
+		t.Errorf("len(Window)=%d, wanted 80", len(w))
+	}
+
+	// shrinking the window is ready immediately
+	if err := s.SetParams(Config{20, 5, 3, 0.95}); err != nil {
+		t.Fatalf("SetParams failed: %v", err)
+	}
+
+	if w := s.Window(); len(w) != 20 || w[9] != 1 || w[10] != 10 {
+		t.Errorf("Window after shrinking=%v, wanted the last 20 items", w)
+	}
+}
@@ -0,0 +1,56 @@
+package change
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// parity is a dataset and the change point other tools report for it; see testdata/parity/README
+type parity struct {
+	Name          string    `json:"name"`
+	MinSampleSize int       `json:"min_sample_size"`
+	Input         []float64 `json:"input"`
+	Want          []struct {
+		Tool       string  `json:"tool"`
+		Index      int     `json:"index"`
+		BeforeMean float64 `json:"before_mean"`
+		AfterMean  float64 `json:"after_mean"`
+	} `json:"want"`
+}
+
+func TestParity(t *testing.T) {
+
+	files, err := filepath.Glob(filepath.Join("testdata", "parity", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no parity fixtures found: %v", err)
+	}
+
+	for _, file := range files {
+		buf, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var p parity
+		if err := json.Unmarshal(buf, &p); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		d := Detector{MinSampleSize: p.MinSampleSize}
+		cp := d.Check(p.Input)
+		if cp == nil {
+			t.Errorf("%s: no change found", p.Name)
+			continue
+		}
+
+		for _, w := range p.Want {
+			if cp.Index != w.Index || math.Abs(cp.Before.Mean()-w.BeforeMean) > 1e-6 || math.Abs(cp.After.Mean()-w.AfterMean) > 1e-6 {
+				t.Errorf("%s: Check=%d (%v -> %v), wanted %d (%v -> %v) as reported by %s",
+					p.Name, cp.Index, cp.Before.Mean(), cp.After.Mean(), w.Index, w.BeforeMean, w.AfterMean, w.Tool)
+			}
+		}
+	}
+}
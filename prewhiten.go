@@ -0,0 +1,67 @@
+package change
+
+// FitAR1 returns the least-squares estimate of the lag-1 autoregressive
+// coefficient of series, for use in Stream.AR or Prewhiten
+func FitAR1(series []float64) float64 {
+	if len(series) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, v := range series {
+		mean += v
+	}
+	mean /= float64(len(series))
+
+	var num, den float64
+	for i := 1; i < len(series); i++ {
+		num += (series[i] - mean) * (series[i-1] - mean)
+		den += (series[i-1] - mean) * (series[i-1] - mean)
+	}
+
+	if den == 0 {
+		return 0
+	}
+
+	return num / den
+}
+
+// Prewhiten returns the innovations of series under the autoregressive model
+// with coefficients ar, most recent lag first.  The result is len(ar) items
+// shorter than series.
+func Prewhiten(series []float64, ar []float64) []float64 {
+	if len(series) <= len(ar) {
+		return nil
+	}
+
+	r := make([]float64, 0, len(series)-len(ar))
+	for i := len(ar); i < len(series); i++ {
+		r = append(r, series[i]-arPredict(series[i-len(ar):i], ar))
+	}
+	return r
+}
+
+// arPredict returns the prediction of the next item after history, which
+// holds the last len(ar) items oldest first
+func arPredict(history, ar []float64) float64 {
+	var p float64
+	for i, c := range ar {
+		p += c * history[len(history)-1-i]
+	}
+	return p
+}
+
+// prewhiten returns the innovation for item, and false while the model is
+// still being primed
+func (s *Stream) prewhiten(item float64) (float64, bool) {
+	p := len(s.AR)
+	if len(s.arHistory) < p {
+		s.arHistory = append(s.arHistory, item)
+		return 0, false
+	}
+
+	e := item - arPredict(s.arHistory, s.AR)
+	copy(s.arHistory, s.arHistory[1:])
+	s.arHistory[p-1] = item
+	return e, true
+}
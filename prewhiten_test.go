@@ -0,0 +1,82 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPrewhiten(t *testing.T) {
+
+	var tests = []struct {
+		series []float64
+		ar     []float64
+		want   []float64
+	}{
+		{[]float64{1, 2, 3}, nil, []float64{1, 2, 3}},
+		{[]float64{1, 2, 3}, []float64{1}, []float64{1, 1}},
+		{[]float64{2, 4, 4, 6}, []float64{0.5}, []float64{3, 2, 4}},
+		{[]float64{1, 1, 2, 3, 5}, []float64{1, 1}, []float64{0, 0, 0}},
+		{[]float64{1}, []float64{1}, nil},
+	}
+
+	for _, tt := range tests {
+		if got := Prewhiten(tt.series, tt.ar); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Prewhiten(%v, %v)=%v, wanted %v", tt.series, tt.ar, got, tt.want)
+		}
+	}
+}
+
+func TestFitAR1(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, phi := range []float64{0, 0.5, 0.9} {
+		w := make([]float64, 5000)
+		for i := 1; i < len(w); i++ {
+			w[i] = phi*w[i-1] + rnd.NormFloat64()
+		}
+
+		if got := FitAR1(w); math.Abs(got-phi) > 0.05 {
+			t.Errorf("FitAR1(phi=%f)=%f", phi, got)
+		}
+	}
+}
+
+func TestStreamAR(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	const phi = 0.8
+
+	// an autocorrelated series with a step in its mean at 150
+	var x float64
+	series := make([]float64, 300)
+	for i := range series {
+		x = phi*x + rnd.NormFloat64()
+		series[i] = x
+		if i >= 150 {
+			series[i] += 20
+		}
+	}
+
+	s := NewStream(100, 20, 5, 0.999)
+	s.AR = []float64{FitAR1(series[:150])}
+
+	// the first item only primes the model, so offsets are one less
+	var found bool
+	for i, v := range series {
+		r := s.Push(v)
+		if r != nil && i < 150 {
+			t.Errorf("Stream with AR found change at %d before the step", r.Offset)
+		}
+		if r != nil && r.Offset == 149 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Stream with AR did not find the change at 150")
+	}
+}
@@ -0,0 +1,61 @@
+package change
+
+import "sort"
+
+// QuantileChange is a change in one of the quantiles tracked by a QuantileStream
+type QuantileChange struct {
+	Quantile float64
+	ChangePoint
+}
+
+// QuantileStream monitors quantiles of a stream of floats for changes.  Each
+// group of items is summarized by its quantiles, and a Stream runs change
+// detection on the series of each quantile, so regressions in the tail of a
+// latency distribution are found even when the mean hardly moves.
+type QuantileStream struct {
+	quantiles []float64
+	streams   []*Stream
+
+	group []float64
+}
+
+// NewQuantileStream constructs a new quantile stream detector.  The given
+// quantiles are computed over each group of groupSize items.  windowSize,
+// minSample and blockSize are as for NewStream, but count groups rather than
+// items.
+func NewQuantileStream(quantiles []float64, groupSize int, windowSize int, minSample int, blockSize int, confidence float64) *QuantileStream {
+	q := &QuantileStream{
+		quantiles: append([]float64(nil), quantiles...),
+		group:     make([]float64, 0, groupSize),
+	}
+
+	for range quantiles {
+		q.streams = append(q.streams, NewStream(windowSize, minSample, blockSize, confidence))
+	}
+
+	return q
+}
+
+// Push adds a float to the stream and returns the changes found in any of the quantiles
+func (q *QuantileStream) Push(item float64) []QuantileChange {
+	q.group = append(q.group, item)
+	if len(q.group) < cap(q.group) {
+		return nil
+	}
+
+	sort.Float64s(q.group)
+
+	var changes []QuantileChange
+	for i, quantile := range q.quantiles {
+		if cp := q.streams[i].Push(nearestRank(q.group, quantile)); cp != nil {
+			changes = append(changes, QuantileChange{quantile, *cp})
+		}
+	}
+
+	q.group = q.group[:0]
+
+	return changes
+}
+
+// Stream returns the stream monitoring the i'th quantile
+func (q *QuantileStream) Stream(i int) *Stream { return q.streams[i] }
@@ -0,0 +1,37 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestQuantileStream(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	q := NewQuantileStream([]float64{0.5, 0.99}, 100, 40, 5, 5, 0.999)
+
+	found := make(map[float64]int)
+	for i := 0; i < 10000; i++ {
+		v := 10 + rnd.Float64()
+
+		// after the change, 2% of requests are slow
+		if i >= 6000 && rnd.Intn(50) == 0 {
+			v = 100
+		}
+
+		for _, c := range q.Push(v) {
+			if _, ok := found[c.Quantile]; !ok {
+				found[c.Quantile] = c.Offset
+			}
+		}
+	}
+
+	if _, ok := found[0.5]; ok {
+		t.Errorf("QuantileStream reported a change in the median")
+	}
+
+	if offset, ok := found[0.99]; !ok || offset != 60 {
+		t.Errorf("QuantileStream p99 change at %d (found=%v), wanted group 60", offset, ok)
+	}
+}
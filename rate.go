@@ -0,0 +1,223 @@
+package change
+
+import (
+	"fmt"
+	"math"
+)
+
+// RateDetector is a change detector for event counts, such as errors per
+// interval, where the Gaussian assumptions of Detector are poor.  Counts of
+// events out of a known number of trials are tested with a binomial
+// likelihood ratio, and counts without trials with a Poisson one.
+type RateDetector struct {
+	// MinSampleSize is the smallest number of intervals allowed on either
+	// side of a change point, as for Detector
+	MinSampleSize int
+
+	// MinConfidence is the likelihood ratio test confidence a change point must exceed to be reported
+	MinConfidence float64
+}
+
+// Check returns the index of a potential change in the rate of events.
+// events[i] is the number of events in the i'th interval.  If trials is not
+// nil, trials[i] is the number of trials in that interval and the rate is the
+// fraction of trials that were events; otherwise the rate is events per
+// interval.  The Before and After statistics of the change point describe the
+// rate and its variance on each side.
+func (d *RateDetector) Check(events, trials []float64) *ChangePoint {
+
+	n := len(events)
+
+	minSampleSize := d.MinSampleSize
+	if minSampleSize <= 0 {
+		minSampleSize = DefaultMinSampleSize
+	}
+
+	exposure := func(i int) float64 { return 1 }
+	if trials != nil {
+		exposure = func(i int) float64 { return trials[i] }
+	}
+
+	var k, t float64
+	for i := range events {
+		k += events[i]
+		t += exposure(i)
+	}
+
+	loglik := poissonLoglik
+	if trials != nil {
+		loglik = binomialLoglik
+	}
+
+	null := loglik(k, t, k/t)
+
+	var maxlr float64
+	var maxIdx int
+	var k1, t1 float64
+
+	// lrs[i] is the likelihood ratio statistic of a change before i
+	lrs := make([]float64, n+1)
+
+	for l := 0; l < n-minSampleSize; l++ {
+		k1 += events[l]
+		t1 += exposure(l)
+		if l+1 < minSampleSize {
+			continue
+		}
+
+		k2, t2 := k-k1, t-t1
+		lr := 2 * (loglik(k1, t1, k1/t1) + loglik(k2, t2, k2/t2) - null)
+		lrs[l+1] = lr
+		if lr > maxlr {
+			maxlr, maxIdx = lr, l+1
+		}
+	}
+
+	if maxIdx == 0 {
+		return nil
+	}
+
+	// the likelihood ratio statistic is asymptotically chi-squared with one degree of freedom
+	conf := math.Erf(math.Sqrt(maxlr / 2))
+	if conf <= d.MinConfidence {
+		return nil
+	}
+
+	var kb, tb float64
+	for i := 0; i < maxIdx; i++ {
+		kb += events[i]
+		tb += exposure(i)
+	}
+	ka, ta := k-kb, t-tb
+
+	before := rateStats(kb/tb, maxIdx, trials != nil)
+	after := rateStats(ka/ta, n-maxIdx, trials != nil)
+
+	// as for Detector, the confidence interval is the positions whose
+	// likelihood ratio is within the 95% point of the chi-squared
+	// distribution of the best
+	lo, hi := maxIdx, maxIdx
+	for lo > minSampleSize && maxlr-lrs[lo-1] < chiSquared95 {
+		lo--
+	}
+	for hi < n-minSampleSize && maxlr-lrs[hi+1] < chiSquared95 {
+		hi++
+	}
+
+	return &ChangePoint{
+		Index:      maxIdx,
+		IndexLow:   lo,
+		IndexHigh:  hi,
+		Difference: after.mean - before.mean,
+		Confidence: conf,
+		Score:      maxlr,
+		Before:     before,
+		After:      after,
+		Offset:     maxIdx,
+		Lag:        n - maxIdx,
+	}
+}
+
+func rateStats(rate float64, n int, binomial bool) Stats {
+	variance := rate
+	if binomial {
+		variance = rate * (1 - rate)
+	}
+	return Stats{mean: rate, variance: variance, n: n}
+}
+
+// xlogy returns x*log(y), taking 0*log(0) as 0
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
+
+// poissonLoglik is the log-likelihood, up to a constant, of k events in exposure t at rate
+func poissonLoglik(k, t, rate float64) float64 { return xlogy(k, rate) - rate*t }
+
+// binomialLoglik is the log-likelihood, up to a constant, of k events in t trials with probability p
+func binomialLoglik(k, t, p float64) float64 { return xlogy(k, p) + xlogy(t-k, 1-p) }
+
+// RateStream monitors a stream of event counts for changes in their rate
+type RateStream struct {
+	windowSize int
+	blockSize  int
+
+	events []float64
+	trials []float64
+
+	items  int
+	bufidx int
+
+	detector *RateDetector
+}
+
+// NewRateStream constructs a new event rate stream detector.  The arguments
+// are as for NewStream, counting intervals, and it panics for the same sizes.
+func NewRateStream(windowSize int, minSample int, blockSize int, confidence float64) *RateStream {
+	if windowSize <= 0 || blockSize <= 0 || blockSize > windowSize {
+		panic(fmt.Sprintf("change: invalid rate stream window size %d and block size %d", windowSize, blockSize))
+	}
+
+	return &RateStream{
+		windowSize: windowSize,
+		blockSize:  blockSize,
+		events:     make([]float64, 0, windowSize),
+		trials:     make([]float64, 0, windowSize),
+
+		detector: &RateDetector{
+			MinSampleSize: minSample,
+			MinConfidence: confidence,
+		},
+	}
+}
+
+// Push adds the number of events and trials observed in an interval and
+// calls the change detector.  Pass a trials value of 0 if the number of
+// trials is unknown.  The rate is the fraction of trials that were events
+// only if every interval in the window has trials, and otherwise it is
+// events per interval.
+func (s *RateStream) Push(events, trials float64) *ChangePoint {
+	if len(s.events) == s.windowSize {
+		copy(s.events, s.events[1:])
+		copy(s.trials, s.trials[1:])
+		s.events = s.events[:s.windowSize-1]
+		s.trials = s.trials[:s.windowSize-1]
+	}
+
+	s.events = append(s.events, events)
+	s.trials = append(s.trials, trials)
+	s.items++
+	s.bufidx++
+
+	if s.bufidx < s.blockSize || s.items < s.windowSize {
+		return nil
+	}
+	s.bufidx = 0
+
+	trialsWindow := s.trials
+	for _, t := range s.trials {
+		if t == 0 {
+			trialsWindow = nil
+			break
+		}
+	}
+
+	cp := s.detector.Check(s.events, trialsWindow)
+	if cp != nil {
+		cp.Offset = s.items - s.windowSize + cp.Index
+	}
+
+	return cp
+}
+
+// PushBool adds a single trial which was an event if b is true
+func (s *RateStream) PushBool(b bool) *ChangePoint {
+	var events float64
+	if b {
+		events = 1
+	}
+	return s.Push(events, 1)
+}
@@ -0,0 +1,115 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRateDetectorPoisson(t *testing.T) {
+
+	// errors per minute, a rare event whose rate triples
+	events := make([]float64, 100)
+	for i := range events {
+		if i%10 == 0 {
+			events[i] = 1
+		}
+		if i >= 60 && i%10 < 3 {
+			events[i] = 1
+		}
+	}
+
+	d := RateDetector{MinSampleSize: 10, MinConfidence: 0.95}
+
+	r := d.Check(events, nil)
+	if r == nil || r.Index != 60 {
+		t.Fatalf("RateDetector returned %v, wanted change at 60", r)
+	}
+
+	if r.IndexLow > r.Index || r.IndexHigh < r.Index || r.IndexLow < 10 || r.IndexHigh > 90 {
+		t.Errorf("interval [%d, %d], wanted it to contain 60 within the min sample size", r.IndexLow, r.IndexHigh)
+	}
+
+	if r.Before.Mean() != 0.1 || r.After.Mean() != 0.3 {
+		t.Errorf("rates before=%f after=%f, wanted 0.1 and 0.3", r.Before.Mean(), r.After.Mean())
+	}
+
+	if r := d.Check(make([]float64, 100), nil); r != nil {
+		t.Errorf("RateDetector found change %v in no events", r)
+	}
+}
+
+func TestRateStream(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	s := NewRateStream(200, 20, 10, 0.999)
+
+	var first *ChangePoint
+	for i := 0; i < 2000 && first == nil; i++ {
+		p := 0.01
+		if i >= 1000 {
+			p = 0.05
+		}
+		first = s.Push(binomial(rnd, 100, p), 100)
+	}
+
+	if first == nil || first.Offset < 980 || first.Offset > 1020 {
+		t.Errorf("RateStream returned %v, wanted change at about 1000", first)
+	}
+
+	b := NewRateStream(40, 5, 5, 0.95)
+	for i := 0; i < 40; i++ {
+		if r := b.PushBool(false); r != nil {
+			t.Errorf("RateStream found change %v in constant booleans", r)
+		}
+	}
+}
+
+func TestRateStreamMixed(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	// every third interval has no count of trials, so the rate is always
+	// events per interval, whichever interval was pushed last
+	s := NewRateStream(200, 20, 1, 0.999)
+
+	var found []*ChangePoint
+	for i := 0; i < 1300; i++ {
+		p := 0.01
+		if i >= 1000 {
+			p = 0.05
+		}
+		trials := 100.0
+		if i%3 == 0 {
+			trials = 0
+		}
+		if cp := s.Push(binomial(rnd, 100, p), trials); cp != nil {
+			found = append(found, cp)
+		}
+	}
+
+	if len(found) == 0 || found[0].Offset < 980 || found[0].Offset > 1020 {
+		t.Fatalf("RateStream found %d changes, wanted the first at about 1000", len(found))
+	}
+
+	// rates are events per interval, about 1 before the change, and not
+	// fractions of trials
+	for _, cp := range found {
+		if math.IsNaN(cp.Confidence) || math.IsNaN(cp.Before.Mean()) || math.IsNaN(cp.After.Mean()) || cp.Before.Mean() < 0.5 {
+			t.Errorf("RateStream change at %d: rates %v and %v confidence %v, wanted events per interval",
+				cp.Offset, cp.Before.Mean(), cp.After.Mean(), cp.Confidence)
+			break
+		}
+	}
+}
+
+func binomial(rnd *rand.Rand, n int, p float64) float64 {
+	var k float64
+	for i := 0; i < n; i++ {
+		if rnd.Float64() < p {
+			k++
+		}
+	}
+	return k
+}
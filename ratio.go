@@ -0,0 +1,92 @@
+package change
+
+// ZeroPolicy is how a RatioStream handles a group whose denominator is zero
+type ZeroPolicy int
+
+const (
+	// SkipZero drops the group, so it does not appear in the window
+	SkipZero ZeroPolicy = iota
+
+	// ZeroRatio treats the ratio of the group as zero
+	ZeroRatio
+
+	// CarryLast repeats the ratio of the previous group, or zero if there
+	// is none
+	CarryLast
+)
+
+func (p ZeroPolicy) String() string {
+	switch p {
+	case SkipZero:
+		return "SkipZero"
+	case ZeroRatio:
+		return "ZeroRatio"
+	case CarryLast:
+		return "CarryLast"
+	}
+	return "ZeroPolicy(?)"
+}
+
+// RatioStream monitors the ratio of two streams of floats, such as errors
+// and requests, for changes.  The numerators and denominators of each group
+// of pairs are summed and a Stream runs change detection on their ratio, so
+// a change in the error rate is not masked or mimicked by a change in
+// traffic.
+type RatioStream struct {
+	groupSize int
+	n         int
+
+	num, den float64
+	last     float64
+
+	// ZeroDenominator is how a group whose denominators sum to zero is
+	// handled
+	ZeroDenominator ZeroPolicy
+
+	stream *Stream
+}
+
+// NewRatioStream constructs a new ratio stream detector.  The ratio is
+// computed over each group of groupSize pairs.  windowSize, minSample and
+// blockSize are as for NewStream, but count groups rather than pairs.
+func NewRatioStream(groupSize int, windowSize int, minSample int, blockSize int, confidence float64) *RatioStream {
+	return &RatioStream{
+		groupSize: groupSize,
+		stream:    NewStream(windowSize, minSample, blockSize, confidence),
+	}
+}
+
+// Push adds a numerator and denominator to the stream and calls the change
+// detector once a group is complete
+func (r *RatioStream) Push(num, den float64) *ChangePoint {
+	r.num += num
+	r.den += den
+	r.n++
+
+	if r.n < r.groupSize {
+		return nil
+	}
+
+	num, den = r.num, r.den
+	r.n, r.num, r.den = 0, 0, 0
+
+	var ratio float64
+	if den != 0 {
+		ratio = num / den
+	} else {
+		switch r.ZeroDenominator {
+		case SkipZero:
+			return nil
+		case ZeroRatio:
+			ratio = 0
+		case CarryLast:
+			ratio = r.last
+		}
+	}
+	r.last = ratio
+
+	return r.stream.Push(ratio)
+}
+
+// Stream returns the stream monitoring the ratios
+func (r *RatioStream) Stream() *Stream { return r.stream }
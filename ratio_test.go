@@ -0,0 +1,61 @@
+package change
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestRatioStreamZero(t *testing.T) {
+
+	var tests = []struct {
+		policy ZeroPolicy
+		want   []float64
+	}{
+		{SkipZero, []float64{0.5, 0.25}},
+		{ZeroRatio, []float64{0.5, 0, 0.25}},
+		{CarryLast, []float64{0.5, 0.5, 0.25}},
+	}
+
+	for _, tt := range tests {
+		r := NewRatioStream(2, 10, 2, 1, 0.95)
+		r.ZeroDenominator = tt.policy
+
+		pairs := [][2]float64{{1, 1}, {1, 3}, {0, 0}, {0, 0}, {1, 2}, {0, 2}}
+		for _, p := range pairs {
+			r.Push(p[0], p[1])
+		}
+
+		w := r.Stream().Window()
+		if got := w[len(w)-len(tt.want):]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RatioStream with %v window=%v, wanted %v", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestRatioStream(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	r := NewRatioStream(10, 40, 5, 5, 0.999)
+
+	var cp *ChangePoint
+	for i := 0; i < 1000 && cp == nil; i++ {
+		// traffic doubles at 300, but the error rate only changes at 600
+		requests := float64(100 + rnd.Intn(10))
+		if i >= 300 {
+			requests *= 2
+		}
+
+		rate := 0.01
+		if i >= 600 {
+			rate = 0.05
+		}
+
+		cp = r.Push(requests*rate*(0.9+0.2*rnd.Float64()), requests)
+	}
+
+	if cp == nil || cp.Offset != 60 {
+		t.Errorf("RatioStream returned %v, wanted change at group 60", cp)
+	}
+}
@@ -0,0 +1,65 @@
+package change
+
+// emit returns cp stamped with the detection time, keeping it as the last
+// change and in the history, or nil if it is suppressed by the Suppressor or
+// MinInterval
+func (s *Stream) emit(cp *ChangePoint) *ChangePoint {
+	now := s.now()
+
+	if s.Suppressor != nil && s.Suppressor.Suppress(now, cp) {
+		s.debug("change point suppressed by suppressor", "offset", cp.Offset)
+		return nil
+	}
+
+	// the confirmation of a reported tentative change point is not a new change
+	confirmation := s.lastChange != nil && s.lastChange.Tentative && !cp.Tentative && s.lastChange.Offset == cp.Offset
+
+	if s.MinInterval > 0 && !confirmation && !s.lastReported.IsZero() && now.Sub(s.lastReported) < s.MinInterval {
+		s.suppressed++
+		s.debug("change point suppressed", "offset", cp.Offset, "suppressed", s.suppressed)
+		return nil
+	}
+
+	cp.Detected = now
+	cp.Shadow = s.Shadow
+	cp.Suppressed = s.suppressed
+	s.lastReported, s.suppressed = now, 0
+
+	last := *cp
+	s.lastChange = &last
+
+	if s.History <= 0 {
+		s.history = nil
+		return cp
+	}
+
+	if len(s.history) >= s.History {
+		drop := len(s.history) - s.History + 1
+		s.history = append(s.history[:0], s.history[drop:]...)
+	}
+	s.history = append(s.history, last)
+
+	return cp
+}
+
+// Recent returns up to n of the most recent change points reported by the
+// stream, oldest first, or nil if n is not positive.  At most History change
+// points are kept.  Tentative change points that are later confirmed appear
+// twice.
+func (s *Stream) Recent(n int) []ChangePoint {
+	if n <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.history[max(0, len(s.history)-n):]
+
+	r := make([]ChangePoint, len(h))
+	for i, cp := range h {
+		cp.Window = append([]float64(nil), cp.Window...)
+		r[i] = cp
+	}
+	return r
+}
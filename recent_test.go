@@ -0,0 +1,123 @@
+package change
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecent(t *testing.T) {
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewStream(40, 10, 10, 0.999)
+	s.History = 2
+	s.now = func() time.Time { return now }
+
+	level := 0.0
+	var offsets []int
+	for i := 0; i < 400; i++ {
+		if i%100 == 0 {
+			level += 10
+		}
+		now = now.Add(time.Second)
+		if cp := s.Push(level + float64(i%3)/10); cp != nil {
+			if !cp.Detected.Equal(now) {
+				t.Errorf("Detected=%v, wanted %v", cp.Detected, now)
+			}
+			offsets = append(offsets, cp.Offset)
+		}
+	}
+
+	if len(offsets) < 3 {
+		t.Fatalf("found %d changes, wanted at least 3", len(offsets))
+	}
+
+	recent := s.Recent(5)
+	if len(recent) != 2 {
+		t.Fatalf("Recent(5)=%d change points, wanted History=2", len(recent))
+	}
+
+	want := offsets[len(offsets)-2:]
+	for i, cp := range recent {
+		if cp.Offset != want[i] {
+			t.Errorf("Recent(5)[%d].Offset=%d, wanted %d", i, cp.Offset, want[i])
+		}
+	}
+
+	if r := s.Recent(1); len(r) != 1 || r[0].Offset != want[1] {
+		t.Errorf("Recent(1)=%v, wanted offset %d", r, want[1])
+	}
+
+	for _, n := range []int{0, -1} {
+		if r := s.Recent(n); r != nil {
+			t.Errorf("Recent(%d)=%v, wanted nil", n, r)
+		}
+	}
+
+	if r := NewStream(40, 10, 10, 0.999).Recent(5); len(r) != 0 {
+		t.Errorf("Recent of a new stream=%v, wanted none", r)
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewStream(40, 10, 10, 0.999)
+	s.MinInterval = 5 * time.Minute
+	s.now = func() time.Time { return now }
+
+	// a series flapping between two levels every 50 items, one item a second
+	var reported []ChangePoint
+	for i := 0; i < 1000; i++ {
+		now = now.Add(time.Second)
+		level := float64(i / 50 % 2 * 10)
+		if cp := s.Push(level + float64(i%3)/10); cp != nil {
+			reported = append(reported, *cp)
+		}
+	}
+
+	if len(reported) < 2 {
+		t.Fatalf("reported %d changes, wanted at least 2", len(reported))
+	}
+
+	for i := 1; i < len(reported); i++ {
+		if d := reported[i].Detected.Sub(reported[i-1].Detected); d < s.MinInterval {
+			t.Errorf("change %d reported %v after the previous one, wanted at least %v", i, d, s.MinInterval)
+		}
+		if reported[i].Suppressed == 0 {
+			t.Errorf("change %d: Suppressed=0, wanted flapping changes to be counted", i)
+		}
+	}
+
+	if reported[0].Suppressed != 0 {
+		t.Errorf("first change: Suppressed=%d, wanted 0", reported[0].Suppressed)
+	}
+}
+
+func TestMinIntervalConfirm(t *testing.T) {
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewStream(40, 10, 10, 0.999)
+	s.MinInterval = time.Hour
+	s.Confirm = 10
+	s.now = func() time.Time { return now }
+
+	var reported []ChangePoint
+	for i := 0; i < 80; i++ {
+		now = now.Add(time.Second)
+		level := 0.0
+		if i >= 25 {
+			level = 10
+		}
+		if cp := s.Push(level + float64(i%3)/10); cp != nil {
+			reported = append(reported, *cp)
+		}
+	}
+
+	// the confirmation is reported even though it is within MinInterval
+	if len(reported) != 2 || !reported[0].Tentative || reported[1].Tentative {
+		t.Errorf("reported=%v, wanted a tentative change and its confirmation", reported)
+	}
+}
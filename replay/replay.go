@@ -0,0 +1,121 @@
+// Package replay runs historical data through change detection streams
+/*
+Replaying a recorded series through several stream configurations and
+scoring the results against known change points makes tuning the stream
+parameters reproducible.
+*/
+package replay
+
+import "github.com/dgryski/go-change"
+
+// Config is the set of parameters used to construct a change.Stream
+type Config = change.Config
+
+// Result is the outcome of replaying a series through a single configuration
+type Result struct {
+	Config Config
+
+	// Changes is every change point reported by the stream, in the order they were reported
+	Changes []change.ChangePoint
+
+	// Delays is the detection delay, in items, for each label that was
+	// detected.  It is counted as the stream counts Lag, so a change
+	// reported exactly at its label has a delay equal to its Lag.
+	Delays []int
+
+	// Detected is the number of labels that were matched by a reported change
+	Detected int
+
+	// Missed is the number of labels that were never matched
+	Missed int
+
+	// FalsePositives is the number of reported changes which matched no label
+	FalsePositives int
+}
+
+// MeanDelay returns the average detection delay over the detected labels
+func (r *Result) MeanDelay() float64 {
+	if len(r.Delays) == 0 {
+		return 0
+	}
+
+	var sum int
+	for _, d := range r.Delays {
+		sum += d
+	}
+
+	return float64(sum) / float64(len(r.Delays))
+}
+
+// Run replays series through a new stream for each configuration.  labels
+// are the indices of the known change points in series, and a change reported
+// within tolerance items of a label is considered to have found it.  If labels
+// is nil, only the Changes field of each result is filled in.
+func Run(series []float64, labels []int, tolerance int, configs ...Config) []Result {
+
+	results := make([]Result, len(configs))
+
+	for i, c := range configs {
+		results[i] = replay(series, labels, tolerance, c)
+	}
+
+	return results
+}
+
+func replay(series []float64, labels []int, tolerance int, c Config) Result {
+
+	s := change.NewStream(c.WindowSize, c.MinSampleSize, c.BlockSize, c.MinConfidence)
+
+	r := Result{Config: c}
+
+	// found tracks the first detection of each label
+	found := make([]bool, len(labels))
+
+	for _, v := range series {
+		cp := s.Push(v)
+		if cp == nil {
+			continue
+		}
+
+		r.Changes = append(r.Changes, *cp)
+
+		if labels == nil {
+			continue
+		}
+
+		matched := false
+		for j, l := range labels {
+			if abs(cp.Offset-l) > tolerance {
+				continue
+			}
+
+			matched = true
+			if !found[j] {
+				found[j] = true
+				// the stream had pushed Offset+Lag items when it reported cp
+				r.Delays = append(r.Delays, cp.Offset+cp.Lag-l)
+			}
+		}
+
+		if !matched {
+			r.FalsePositives++
+		}
+	}
+
+	for _, f := range found {
+		if f {
+			r.Detected++
+		} else {
+			r.Missed++
+		}
+	}
+
+	return r
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
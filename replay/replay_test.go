@@ -0,0 +1,51 @@
+package replay
+
+import "testing"
+
+func TestRun(t *testing.T) {
+
+	var series []float64
+	for i := 0; i < 200; i++ {
+		v := 1.0
+		if i >= 100 {
+			v = 2.0
+		}
+		series = append(series, v)
+	}
+
+	configs := []Config{
+		{WindowSize: 40, MinSampleSize: 5, BlockSize: 5, MinConfidence: 0.95},
+		{WindowSize: 60, MinSampleSize: 10, BlockSize: 10, MinConfidence: 0.95},
+	}
+
+	results := Run(series, []int{100}, 5, configs...)
+
+	if len(results) != len(configs) {
+		t.Fatalf("Run returned %d results, wanted %d", len(results), len(configs))
+	}
+
+	for _, r := range results {
+		if r.Detected != 1 || r.Missed != 0 {
+			t.Errorf("Run(%+v) detected=%d missed=%d, wanted 1 and 0", r.Config, r.Detected, r.Missed)
+		}
+
+		if r.FalsePositives != 0 {
+			t.Errorf("Run(%+v) false positives=%d, wanted 0", r.Config, r.FalsePositives)
+		}
+
+		if len(r.Delays) != 1 || r.Delays[0] < 0 {
+			t.Errorf("Run(%+v) delays=%v, wanted one non-negative delay", r.Config, r.Delays)
+			continue
+		}
+
+		// the step is found exactly at its label, so the delay is the stream's lag
+		if cp := r.Changes[0]; cp.Offset != 100 || r.Delays[0] != cp.Lag {
+			t.Errorf("Run(%+v) delay=%d for a change at %d, wanted its Lag %d", r.Config, r.Delays[0], cp.Offset, cp.Lag)
+		}
+	}
+
+	results = Run(series, nil, 5, configs[0])
+	if len(results[0].Changes) == 0 || results[0].Detected != 0 {
+		t.Errorf("Run without labels changes=%d detected=%d", len(results[0].Changes), results[0].Detected)
+	}
+}
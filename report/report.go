@@ -0,0 +1,74 @@
+// Package report renders change detection results as an HTML page
+/*
+The default template plots each series with flot and marks the change points
+found.  It defines the blocks "head", "header" and "footer", which ParseFS can
+override to brand or extend the page; a completely different page can be
+rendered by passing any template that accepts a *Data to Write.
+*/
+package report
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"io/fs"
+
+	"github.com/dgryski/go-change"
+)
+
+//go:embed report.html
+var defaultTemplate string
+
+var defaultTmpl = template.Must(template.New("report").Parse(defaultTemplate))
+
+// Point is an x, y pair plotted on the graph.  x is an item offset, or a time
+// in milliseconds since the epoch if the report has a time axis.
+type Point [2]float64
+
+// Series is the graph data and change points found for a single input
+type Series struct {
+	Label        string
+	GraphData    []Point
+	ChangePoints []change.ChangePoint
+}
+
+// Marking is a vertical line drawn on the graph at x, in the same units as
+// the points
+type Marking struct {
+	X     float64
+	Color string
+}
+
+// Data is the value the report template is executed with
+type Data struct {
+	// YMin is the minimum of the y axis
+	YMin int
+
+	// Time is set if the x axis is time
+	Time bool
+
+	Series []Series
+
+	// ChangePoints are the markings drawn on the graph
+	ChangePoints []Marking
+}
+
+// Default returns a copy of the default report template
+func Default() *template.Template {
+	return template.Must(defaultTmpl.Clone())
+}
+
+// ParseFS returns a copy of the default template with the templates in the
+// files matching patterns in fsys added, so they can redefine its blocks
+func ParseFS(fsys fs.FS, patterns ...string) (*template.Template, error) {
+	return Default().ParseFS(fsys, patterns...)
+}
+
+// Write renders the report for d to w using t, or the default template if t
+// is nil
+func Write(w io.Writer, t *template.Template, d *Data) error {
+	if t == nil {
+		t = defaultTmpl
+	}
+	return t.Execute(w, d)
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWrite(t *testing.T) {
+
+	d := &Data{
+		YMin:         5,
+		Series:       []Series{{Label: "latency", GraphData: []Point{{10, 1}, {20, 2}}}},
+		ChangePoints: []Marking{{X: 15, Color: "#d00"}},
+	}
+
+	fsys := fstest.MapFS{
+		"brand.tmpl": {Data: []byte(`{{ define "header" }}<h1>Acme {{ len .Series }}</h1>{{ end }}`)},
+	}
+
+	branded, err := ParseFS(fsys, "*.tmpl")
+	if err != nil {
+		t.Fatalf("ParseFS failed: %v", err)
+	}
+
+	var tests = []struct {
+		name string
+		tmpl *template.Template
+		want []string
+	}{
+		{"default", nil, []string{"latency", "min:  5", "from:  15", "<title>Change points</title>"}},
+		{"branded", branded, []string{"latency", "<h1>Acme 1</h1>", "<title>Change points</title>"}},
+		{"custom", template.Must(template.New("x").Parse(`{{ range .Series }}{{ .Label }}{{ end }}`)), []string{"latency"}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Write(&buf, tt.tmpl, d); err != nil {
+			t.Errorf("Write(%s) failed: %v", tt.name, err)
+			continue
+		}
+
+		for _, w := range tt.want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("Write(%s) missing %q:\n%s", tt.name, w, buf.String())
+			}
+		}
+	}
+
+	// branding one report must not change the default
+	var buf bytes.Buffer
+	Write(&buf, nil, d)
+	if strings.Contains(buf.String(), "Acme") {
+		t.Errorf("ParseFS modified the default template")
+	}
+}
@@ -0,0 +1,22 @@
+package change
+
+// Residuals returns series minus the values expected by a baseline model
+func Residuals(series, expected []float64) []float64 {
+	r := make([]float64, len(series))
+	for i, v := range series {
+		r[i] = v - expected[i]
+	}
+	return r
+}
+
+// Forecast returns a function suitable for Stream.Expected that returns the
+// precomputed forecast values in order.  Items beyond the end of the forecast
+// are expected to be zero, so detection runs on the raw values.
+func Forecast(values []float64) func(n int) float64 {
+	return func(n int) float64 {
+		if n < len(values) {
+			return values[n]
+		}
+		return 0
+	}
+}
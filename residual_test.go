@@ -0,0 +1,34 @@
+package change
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStreamExpected(t *testing.T) {
+
+	// a linear growth trend that the baseline model already predicts
+	growth := func(n int) float64 { return 0.1 * float64(n) }
+
+	s := NewStream(40, 5, 5, 0.95)
+	s.Expected = growth
+
+	var r *ChangePoint
+	for i := 0; i < 200; i++ {
+		v := growth(i) + 0.1*float64(i%2)
+		if i >= 120 {
+			v += 5
+		}
+		if cp := s.Push(v); cp != nil && r == nil {
+			r = cp
+		}
+	}
+
+	if r == nil || r.Offset != 120 {
+		t.Errorf("Stream with baseline returned %v, wanted change at 120", r)
+	}
+
+	if got := Residuals([]float64{3, 4}, []float64{1, 1}); !reflect.DeepEqual(got, []float64{2, 3}) {
+		t.Errorf("Residuals=%v, wanted [2 3]", got)
+	}
+}
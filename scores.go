@@ -0,0 +1,26 @@
+package change
+
+// Scores returns the score and t-test confidence that Check computes for
+// splitting window before each index, for plotting or for choosing
+// thresholds offline.  The confidences are adjusted for autocorrelation if
+// AdjustAutocorrelation is set, as in Check.  Indexes too close to either
+// end of the window for the detector's MinSampleSize have a score and
+// confidence of zero.
+func (d *Detector) Scores(window []float64) (score, confidence []float64) {
+
+	n := len(window)
+
+	score = make([]float64, n)
+	confidence = make([]float64, n)
+
+	sp := newSplits(window)
+	minSampleSize := d.minSampleSize()
+
+	for l := minSampleSize; l < (n - minSampleSize + 1); l++ {
+		score[l] = sp.score(l)
+		before, after := sp.stats(l)
+		confidence[l], _ = d.confidence(window, l, before, after)
+	}
+
+	return score, confidence
+}
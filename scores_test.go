@@ -0,0 +1,60 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestScores(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	// AR(1) noise, so that AdjustAutocorrelation changes the confidence
+	window := make([]float64, 200)
+	var noise float64
+	for i := range window {
+		noise = 0.5*noise + rnd.NormFloat64()
+		window[i] = noise
+		if i >= 120 {
+			window[i] += 2
+		}
+	}
+
+	for _, adjust := range []bool{false, true} {
+		d := &Detector{MinSampleSize: 20, MinConfidence: 0.99, AdjustAutocorrelation: adjust}
+
+		score, confidence := d.Scores(window)
+		if len(score) != len(window) || len(confidence) != len(window) {
+			t.Fatalf("Scores returned %d scores and %d confidences, wanted %d", len(score), len(confidence), len(window))
+		}
+
+		for _, i := range []int{0, 19, 181, 199} {
+			if score[i] != 0 || confidence[i] != 0 {
+				t.Errorf("Scores at %d=%v %v, wanted 0 within the minimum sample size", i, score[i], confidence[i])
+			}
+		}
+
+		cp := d.Check(window)
+		if cp == nil {
+			t.Fatalf("Check(AdjustAutocorrelation=%v) found no change", adjust)
+		}
+
+		best := 0
+		for i := range score {
+			if score[i] > score[best] {
+				best = i
+			}
+		}
+
+		if best != cp.Index || score[best] != cp.Score || confidence[best] != cp.Confidence {
+			t.Errorf("AdjustAutocorrelation=%v: best score at %d=%v %v, wanted Check's %d %v %v", adjust, best, score[best], confidence[best], cp.Index, cp.Score, cp.Confidence)
+		}
+	}
+
+	// with autocorrelated noise the adjustment lowers the confidence
+	_, adjusted := (&Detector{MinSampleSize: 20, AdjustAutocorrelation: true}).Scores(window)
+	_, unadjusted := (&Detector{MinSampleSize: 20}).Scores(window)
+	if adjusted[100] >= unadjusted[100] {
+		t.Errorf("Scores confidence at 100 with AdjustAutocorrelation=%v, wanted below %v", adjusted[100], unadjusted[100])
+	}
+}
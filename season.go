@@ -0,0 +1,16 @@
+package change
+
+// deseason returns item less the item one season earlier, or false while the
+// first season is being collected
+func (s *Stream) deseason(item float64) (float64, bool) {
+	if len(s.season) < s.Season {
+		s.season = append(s.season, item)
+		return 0, false
+	}
+
+	// season is a ring buffer; seasonIdx is the oldest item
+	d := item - s.season[s.seasonIdx]
+	s.season[s.seasonIdx] = item
+	s.seasonIdx = (s.seasonIdx + 1) % s.Season
+	return d, true
+}
@@ -0,0 +1,57 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStreamSeason(t *testing.T) {
+
+	const period = 100
+
+	// a nightly batch job raises the level for 20 items each period, and
+	// from item 750 there is a real change
+	series := func(rnd *rand.Rand, i int) float64 {
+		v := 10 + 0.1*rnd.NormFloat64()
+		if i%period >= 60 && i%period < 80 {
+			v += 5
+		}
+		if i >= 750 {
+			v += 3
+		}
+		return v
+	}
+
+	var tests = []struct {
+		season int
+		early  bool
+	}{
+		{0, true},
+		{period, false},
+	}
+
+	for _, tt := range tests {
+		rnd := rand.New(rand.NewSource(1))
+
+		s := NewStream(60, 10, 5, 0.999)
+		s.Season = tt.season
+
+		var early, late bool
+		for i := 0; i < 1000; i++ {
+			if cp := s.Push(series(rnd, i)); cp != nil {
+				if i < 750 {
+					early = true
+				} else {
+					late = true
+				}
+			}
+		}
+
+		if early != tt.early {
+			t.Errorf("Season=%d: change before 750=%v, wanted %v", tt.season, early, tt.early)
+		}
+		if !late {
+			t.Errorf("Season=%d: real change not found", tt.season)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package change
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckSizes(t *testing.T) {
+
+	// a step halfway through a window of n items
+	step := func(n int) []float64 {
+		w := make([]float64, n)
+		for i := range w {
+			w[i] = float64(i % 2)
+			if i >= n/2 {
+				w[i] += 10
+			}
+		}
+		return w
+	}
+
+	var tests = []struct {
+		n         int
+		minSample int
+		want      int // index of the change, or -1 for none
+	}{
+		{0, 2, -1},
+		{1, 2, -1},
+		{2, 2, -1},
+		{3, 2, -1},
+		{4, 2, 2},
+		{5, 2, 2},
+		{4, 1, 2}, // raised to 2
+		{3, 1, -1},
+		{59, 0, -1}, // DefaultMinSampleSize
+		{60, 0, 30},
+		{60, -1, 30},
+		{19, 10, -1},
+		{20, 10, 10},
+	}
+
+	for _, tt := range tests {
+		d := Detector{MinSampleSize: tt.minSample, MinConfidence: 0.9}
+		window := step(tt.n)
+
+		got := -1
+		if cp := d.Check(window); cp != nil {
+			got = cp.Index
+		}
+		if got != tt.want {
+			t.Errorf("Check(n=%d, MinSampleSize=%d)=%d, wanted %d", tt.n, tt.minSample, got, tt.want)
+		}
+
+		weights := make([]float64, tt.n)
+		for i := range weights {
+			weights[i] = 1
+		}
+		got = -1
+		if cp := d.CheckWeighted(window, weights); cp != nil {
+			got = cp.Index
+		}
+		if got != tt.want {
+			t.Errorf("CheckWeighted(n=%d, MinSampleSize=%d)=%d, wanted %d", tt.n, tt.minSample, got, tt.want)
+		}
+
+		if score, _ := d.Scores(window); len(score) != tt.n {
+			t.Errorf("Scores(n=%d) returned %d scores", tt.n, len(score))
+		}
+	}
+}
+
+func TestNewStreamSizes(t *testing.T) {
+
+	var tests = []struct {
+		window, block int
+		ok            bool
+	}{
+		{0, 0, false},
+		{1, 0, false},
+		{1, 1, true},
+		{1, 2, false},
+		{10, 10, true},
+		{-1, 1, false},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if r := recover(); (r == nil) != tt.ok {
+					t.Errorf("NewStream(%d, %d) panic=%v, wanted ok=%v", tt.window, tt.block, r, tt.ok)
+				}
+			}()
+
+			s := NewStream(tt.window, 2, tt.block, 0.9)
+			for i := 0; i < 3*tt.window; i++ {
+				s.Push(float64(i))
+			}
+		}()
+	}
+}
+
+func TestNewTimeStreamSizes(t *testing.T) {
+
+	var tests = []struct {
+		window, block time.Duration
+		ok            bool
+	}{
+		{0, 0, false},
+		{time.Minute, 0, false},
+		{time.Minute, -time.Second, false},
+		{0, time.Second, false},
+		{time.Minute, time.Second, true},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if r := recover(); (r == nil) != tt.ok {
+					t.Errorf("NewTimeStream(%v, %v) panic=%v, wanted ok=%v", tt.window, tt.block, r, tt.ok)
+				}
+			}()
+
+			s := NewTimeStream(tt.window, tt.block, 2, 0.9)
+			for i := 0; i < 10; i++ {
+				s.Push(start.Add(time.Duration(i)*time.Second), float64(i))
+			}
+		}()
+	}
+}
+
+func TestNewRateStreamSizes(t *testing.T) {
+
+	var tests = []struct {
+		window, block int
+		ok            bool
+	}{
+		{0, 0, false},
+		{0, 1, false},
+		{1, 0, false},
+		{1, 2, false},
+		{1, 1, true},
+		{10, 10, true},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if r := recover(); (r == nil) != tt.ok {
+					t.Errorf("NewRateStream(%d, %d) panic=%v, wanted ok=%v", tt.window, tt.block, r, tt.ok)
+				}
+			}()
+
+			s := NewRateStream(tt.window, 2, tt.block, 0.9)
+			for i := 0; i < 3*tt.window; i++ {
+				s.Push(float64(i%3), 0)
+			}
+		}()
+	}
+}
@@ -0,0 +1,20 @@
+package change
+
+// trailingMean returns the mean of item and the Smooth-1 items before it, or
+// false while the first Smooth items are being collected
+func (s *Stream) trailingMean(item float64) (float64, bool) {
+	s.smoothSum += item
+	if len(s.smooth) < s.Smooth {
+		s.smooth = append(s.smooth, item)
+		if len(s.smooth) < s.Smooth {
+			return 0, false
+		}
+		return s.smoothSum / float64(s.Smooth), true
+	}
+
+	// smooth is a ring buffer; smoothIdx is the oldest item
+	s.smoothSum -= s.smooth[s.smoothIdx]
+	s.smooth[s.smoothIdx] = item
+	s.smoothIdx = (s.smoothIdx + 1) % s.Smooth
+	return s.smoothSum / float64(s.Smooth), true
+}
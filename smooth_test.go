@@ -0,0 +1,60 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStreamSmooth(t *testing.T) {
+
+	s := NewStream(10, 2, 1, 0.99)
+	s.Smooth = 3
+
+	var got []float64
+	for _, v := range []float64{3, 6, 9, 0, 0, 0} {
+		if v, _, ok := s.preprocess(v, 1); ok {
+			got = append(got, v)
+		}
+	}
+
+	want := []float64{6, 5, 3, 0}
+	if len(got) != len(want) {
+		t.Fatalf("smoothed=%v, wanted %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("smoothed=%v, wanted %v", got, want)
+		}
+	}
+
+	// a trailing average finds a step about (Smooth-1)/2 items late
+	const step = 500
+	for _, smooth := range []int{0, 9} {
+		rnd := rand.New(rand.NewSource(1))
+
+		s := NewStream(100, 20, 1, 0.99999)
+		s.Smooth = smooth
+		s.Detector().AdjustAutocorrelation = true
+
+		offset := -1
+		for i := 0; i < 700 && offset < 0; i++ {
+			v := rnd.NormFloat64()
+			if i >= step {
+				v += 2
+			}
+			if cp := s.Push(v); cp != nil {
+				offset = cp.Offset
+			}
+		}
+
+		// offsets count smoothed items, which start Smooth-1 items late
+		if smooth > 1 {
+			offset += smooth - 1
+		}
+
+		lag := offset - step
+		if want := max(0, smooth-1) / 2; offset < 0 || lag < want-3 || lag > want+3 {
+			t.Errorf("Smooth=%d: change found at %d, wanted %d+%d", smooth, offset, step, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package change
+
+// Snapshot is a consistent view of the state of a stream, for status pages
+// and debug dumps.  It shares no memory with the stream.
+type Snapshot struct {
+	Config Config
+
+	// Items is the number of items added to the window, the count that
+	// offsets are relative to
+	Items int
+
+	// Window is a copy of the data window
+	Window []float64
+
+	// Checks is the number of times the window has been checked
+	Checks int
+
+	// Score and Confidence are those of the best split found by the most
+	// recent check, whether or not it was reported as a change point
+	Score, Confidence float64
+
+	// LastChange is the most recent change point returned by Push, or nil
+	LastChange *ChangePoint
+}
+
+// Snapshot returns the current state of the stream.  It may be called
+// concurrently with Push.
+func (s *Stream) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := Snapshot{
+		Config: Config{
+			WindowSize:    s.windowSize,
+			MinSampleSize: s.detector.MinSampleSize,
+			BlockSize:     s.blockSize,
+			MinConfidence: s.detector.MinConfidence,
+		},
+		Items:      s.items,
+		Window:     append([]float64(nil), s.data...),
+		Checks:     s.checks,
+		Score:      s.lastScore,
+		Confidence: s.lastConfidence,
+	}
+
+	if s.lastChange != nil {
+		last := *s.lastChange
+		last.Window = append([]float64(nil), last.Window...)
+		snap.LastChange = &last
+	}
+
+	return snap
+}
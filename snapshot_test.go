@@ -0,0 +1,57 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	s := NewStream(100, 20, 10, 0.999)
+
+	snap := s.Snapshot()
+	if snap.Items != 0 || snap.Checks != 0 || snap.LastChange != nil {
+		t.Errorf("Snapshot of a new stream=%+v, wanted no items, checks or change", snap)
+	}
+
+	want := Config{WindowSize: 100, MinSampleSize: 20, BlockSize: 10, MinConfidence: 0.999}
+	if snap.Config != want {
+		t.Errorf("Snapshot.Config=%+v, wanted %+v", snap.Config, want)
+	}
+
+	var last *ChangePoint
+	for i := 0; i < 200; i++ {
+		v := 10 + rnd.NormFloat64()
+		if i >= 150 {
+			v += 5
+		}
+		if cp := s.Push(v); cp != nil {
+			last = cp
+		}
+	}
+
+	if last == nil {
+		t.Fatalf("no change found")
+	}
+
+	snap = s.Snapshot()
+	if snap.Items != 200 || snap.Checks != 11 || len(snap.Window) != 100 {
+		t.Errorf("Snapshot items=%d checks=%d window=%d, wanted 200 11 100", snap.Items, snap.Checks, len(snap.Window))
+	}
+
+	if snap.LastChange == nil || snap.LastChange.Offset != last.Offset {
+		t.Errorf("Snapshot.LastChange=%v, wanted offset %d", snap.LastChange, last.Offset)
+	}
+
+	if snap.Score != last.Score || snap.Confidence != last.Confidence {
+		t.Errorf("Snapshot score=%v confidence=%v, wanted %v %v", snap.Score, snap.Confidence, last.Score, last.Confidence)
+	}
+
+	// the snapshot does not share the window
+	snap.Window[0] = -1
+	if s.WindowCopy()[0] == -1 {
+		t.Errorf("Snapshot.Window shares memory with the stream")
+	}
+}
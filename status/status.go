@@ -0,0 +1,163 @@
+// Package status provides an HTTP handler showing the state of change detection streams
+/*
+Register the streams being monitored with a Handler and serve it on a debug
+port, as with expvar:
+
+	h := status.NewHandler()
+	h.Add("api.latency", s)
+	http.Handle("/debug/change", h)
+
+The page shows a sparkline of each stream's window with its parameters, the
+score and confidence of its last check, its last change point and the recent
+change points kept by the stream's History option.  Requests
+with format=json get the same state as JSON.
+*/
+package status
+
+import (
+	"encoding/json"
+	"html/template"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dgryski/go-change"
+)
+
+// Handler is an http.Handler rendering the state of a set of streams
+type Handler struct {
+	mu      sync.Mutex
+	streams map[string]*change.Stream
+}
+
+// NewHandler returns a handler with no streams
+func NewHandler() *Handler {
+	return &Handler{streams: make(map[string]*change.Stream)}
+}
+
+// Add registers s under name, replacing any stream already registered with that name
+func (h *Handler) Add(name string, s *change.Stream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.streams[name] = s
+}
+
+// Remove unregisters the stream with the given name
+func (h *Handler) Remove(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.streams, name)
+}
+
+// Stream is the state of a named stream as served by the handler
+type Stream struct {
+	Name string
+	change.Snapshot
+
+	// Recent are the change points in the stream's history, oldest first
+	Recent []change.ChangePoint
+}
+
+// recent is the number of change points from each stream's history to show
+const recent = 10
+
+// snapshots returns the state of the registered streams in order of name
+func (h *Handler) snapshots() []Stream {
+	h.mu.Lock()
+	streams := make([]Stream, 0, len(h.streams))
+	for name, s := range h.streams {
+		streams = append(streams, Stream{Name: name, Snapshot: s.Snapshot(), Recent: s.Recent(recent)})
+	}
+	h.mu.Unlock()
+
+	sort.Slice(streams, func(i, j int) bool { return streams[i].Name < streams[j].Name })
+	return streams
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	streams := h.snapshots()
+
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(streams)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, streams); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline returns values as a line of block characters at most width
+// columns wide, averaging values that share a column.  NaNs and infinities
+// are skipped, and a column with no finite values is blank.
+func Sparkline(values []float64, width int) string {
+	n := len(values)
+	cols := min(n, width)
+	if cols <= 0 {
+		return ""
+	}
+
+	sums := make([]float64, cols)
+	counts := make([]int, cols)
+	for i, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		sums[i*cols/n] += v
+		counts[i*cols/n]++
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i := range sums {
+		if counts[i] == 0 {
+			continue
+		}
+		sums[i] /= float64(counts[i])
+		lo, hi = math.Min(lo, sums[i]), math.Max(hi, sums[i])
+	}
+
+	var line strings.Builder
+	for i, v := range sums {
+		if counts[i] == 0 {
+			line.WriteByte(' ')
+			continue
+		}
+		level := 0
+		if hi > lo {
+			// halved so that the range of large values doesn't overflow
+			level = int((v/2 - lo/2) / (hi/2 - lo/2) * float64(len(sparks)-1))
+		}
+		line.WriteRune(sparks[max(0, min(level, len(sparks)-1))])
+	}
+	return line.String()
+}
+
+var page = template.Must(template.New("status").Funcs(template.FuncMap{
+	"sparkline": Sparkline,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>change detection</title></head>
+<body>
+<table>
+<tr><th>stream</th><th>window</th><th>items</th><th>checks</th><th>score</th><th>confidence</th><th>last change</th><th>recent changes</th><th>parameters</th></tr>
+{{range .}}<tr>
+<td>{{.Name}}</td>
+<td style="font-family: monospace">{{sparkline .Window 80}}</td>
+<td>{{.Items}}</td>
+<td>{{.Checks}}</td>
+<td>{{printf "%.4g" .Score}}</td>
+<td>{{printf "%.4f" .Confidence}}</td>
+<td>{{with .LastChange}}offset {{.Offset}}: {{printf "%.4g" .Before.Mean}} &rarr; {{printf "%.4g" .After.Mean}} (confidence {{printf "%.4f" .Confidence}}){{else}}none{{end}}</td>
+<td>{{range .Recent}}{{.Detected.Format "2006-01-02 15:04:05"}} offset {{.Offset}}: {{printf "%+.4g" .Difference}}<br>{{end}}</td>
+<td>window {{.Config.WindowSize}}, min sample {{.Config.MinSampleSize}}, block {{.Config.BlockSize}}, min confidence {{.Config.MinConfidence}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,79 @@
+package status
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestHandler(t *testing.T) {
+
+	s := change.NewStream(40, 10, 10, 0.999)
+	s.History = 5
+	for i := 0; i < 40; i++ {
+		v := 1.0
+		if i >= 20 {
+			v = 10
+		}
+		s.Push(v + float64(i%3)/10)
+	}
+
+	h := NewHandler()
+	h.Add("latency", s)
+	h.Add("errors", change.NewStream(40, 10, 10, 0.999))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{"latency", "errors", "offset 20", "window 40"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %q:\n%s", want, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/?format=json", nil))
+
+	var streams []Stream
+	if err := json.Unmarshal(rec.Body.Bytes(), &streams); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(streams) != 2 || streams[0].Name != "errors" || streams[1].Name != "latency" || streams[1].Items != 40 || len(streams[1].Recent) != 1 {
+		t.Errorf("json=%+v, wanted errors and latency with 40 items and a recent change", streams)
+	}
+
+	h.Remove("errors")
+	if got := h.snapshots(); len(got) != 1 {
+		t.Errorf("snapshots after Remove=%d, wanted 1", len(got))
+	}
+}
+
+func TestSparkline(t *testing.T) {
+
+	var tests = []struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		{nil, 10, ""},
+		{[]float64{1, 1, 1}, 10, "▁▁▁"},
+		{[]float64{0, 7}, 10, "▁█"},
+		{[]float64{0, 0, 7, 7}, 2, "▁█"},
+		{[]float64{0, math.NaN(), 7}, 10, "▁ █"},
+		{[]float64{math.Inf(1), 0, math.Inf(-1), 7}, 10, " ▁ █"},
+		{[]float64{math.NaN(), math.NaN()}, 10, "  "},
+		{[]float64{-math.MaxFloat64, math.MaxFloat64}, 10, "▁█"},
+		{[]float64{1, 2}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Sparkline(tt.values, tt.width); got != tt.want {
+			t.Errorf("Sparkline(%v, %d)=%q, wanted %q", tt.values, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package change
+
+import "fmt"
+
+// Stream32 is a Stream that keeps its window as float32 values, halving its
+// memory for agents that run many streams on small devices.  Values are
+// widened to float64 for each check, so only their storage loses precision.
+// It supports none of the optional preprocessing stages of Stream.
+type Stream32 struct {
+	windowSize int
+	blockSize  int
+
+	data []float32
+
+	items int
+
+	buffer []float32
+	bufidx int
+
+	detector *Detector
+}
+
+// NewStream32 constructs a new float32 stream detector.  Like NewStream, it
+// panics if windowSize is not positive or blockSize is not between 1 and
+// windowSize.
+func NewStream32(windowSize int, minSample int, blockSize int, confidence float64) *Stream32 {
+	if windowSize <= 0 || blockSize <= 0 || blockSize > windowSize {
+		panic(fmt.Sprintf("change: invalid stream window size %d and block size %d", windowSize, blockSize))
+	}
+
+	return &Stream32{
+		windowSize: windowSize,
+		blockSize:  blockSize,
+		data:       make([]float32, windowSize),
+		buffer:     make([]float32, blockSize),
+
+		detector: &Detector{
+			MinSampleSize: minSample,
+			MinConfidence: confidence,
+		},
+	}
+}
+
+// Push adds a float to the stream and calls the change detector
+func (s *Stream32) Push(item float32) *ChangePoint {
+
+	s.buffer[s.bufidx] = item
+	s.bufidx++
+	s.items++
+
+	if s.bufidx < s.blockSize {
+		return nil
+	}
+
+	k := s.bufidx
+	copy(s.data[0:], s.data[k:])
+	copy(s.data[s.windowSize-k:], s.buffer[:k])
+	s.bufidx = 0
+
+	if s.items < s.windowSize {
+		return nil
+	}
+
+	window := make([]float64, s.windowSize)
+	for i, v := range s.data {
+		window[i] = float64(v)
+	}
+
+	cp := s.detector.Check(window)
+	if cp != nil {
+		cp.Offset = s.items - s.windowSize + cp.Index
+	}
+
+	return cp
+}
+
+// Detector returns the detector run on the window, so its options can be set
+func (s *Stream32) Detector() *Detector { return s.detector }
+
+// Window returns the current data window.  This should be treated as read-only
+func (s *Stream32) Window() []float32 { return s.data }
@@ -0,0 +1,39 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStream32(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	s := NewStream(100, 20, 10, 0.999)
+	s32 := NewStream32(100, 20, 10, 0.999)
+
+	var found int
+	for i := 0; i < 500; i++ {
+		v := float32(10 + rnd.NormFloat64())
+		if i >= 250 {
+			v += 2
+		}
+
+		cp, cp32 := s.Push(float64(v)), s32.Push(v)
+		if (cp == nil) != (cp32 == nil) {
+			t.Fatalf("item %d: Stream32=%v, wanted %v", i, cp32, cp)
+		}
+		if cp == nil {
+			continue
+		}
+
+		found++
+		if cp32.Offset != cp.Offset || cp32.Difference != cp.Difference {
+			t.Errorf("item %d: Stream32 offset=%d difference=%v, wanted %d %v", i, cp32.Offset, cp32.Difference, cp.Offset, cp.Difference)
+		}
+	}
+
+	if found == 0 {
+		t.Errorf("no change found")
+	}
+}
@@ -0,0 +1,110 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary describes the items observed in an interval by a metrics system
+// that does not expose raw samples.  Push one of its derived statistics, such
+// as Mean, to a Stream to detect changes in it.
+type Summary struct {
+	Count float64
+	Sum   float64
+
+	// SumSq is the sum of the squares of the items.  It is only needed for Stddev.
+	SumSq float64
+
+	Min, Max float64
+}
+
+// Mean returns the mean of the items in the interval
+func (s Summary) Mean() float64 { return s.Sum / s.Count }
+
+// Stddev returns the standard deviation of the items in the interval.  It
+// requires SumSq to be set, and returns 0 for fewer than two items.
+func (s Summary) Stddev() float64 {
+	if s.Count < 2 {
+		return 0
+	}
+	return math.Sqrt((s.SumSq - s.Sum*s.Sum/s.Count) / (s.Count - 1))
+}
+
+// Histogram is a bucketed distribution of the items in an interval, in the
+// cumulative form used by Prometheus.
+type Histogram struct {
+	// Bounds are the inclusive upper bounds of the buckets, in increasing
+	// order.  The last bound may be +Inf.
+	Bounds []float64
+
+	// Counts are the number of items less than or equal to each bound
+	Counts []float64
+}
+
+// Total returns the number of items in the histogram
+func (h Histogram) Total() float64 {
+	if len(h.Counts) == 0 {
+		return 0
+	}
+	return h.Counts[len(h.Counts)-1]
+}
+
+// Quantile estimates the q-th quantile of the items, 0 <= q <= 1, by linear
+// interpolation within the bucket that contains it.  The lowest bucket is
+// taken to start at 0, or at its bound if that is negative.  Quantiles
+// falling in a +Inf bucket are reported as the largest finite bound.
+func (h Histogram) Quantile(q float64) float64 {
+	total := h.Total()
+	if total == 0 {
+		return math.NaN()
+	}
+
+	rank := q * total
+	i := sort.Search(len(h.Counts), func(i int) bool { return h.Counts[i] >= rank })
+	if i == len(h.Counts) {
+		i--
+	}
+
+	upper := h.Bounds[i]
+	if math.IsInf(upper, 1) {
+		if i == 0 {
+			return math.NaN()
+		}
+		return h.Bounds[i-1]
+	}
+
+	var lower, below float64
+	if i > 0 {
+		lower, below = h.Bounds[i-1], h.Counts[i-1]
+	} else if upper < 0 {
+		return upper
+	}
+
+	inBucket := h.Counts[i] - below
+	if inBucket == 0 {
+		return upper
+	}
+
+	return lower + (upper-lower)*(rank-below)/inBucket
+}
+
+// Mean estimates the mean of the items from the midpoints of the buckets.
+// Items in a +Inf bucket are counted at the largest finite bound.
+func (h Histogram) Mean() float64 {
+	total := h.Total()
+	if total == 0 {
+		return math.NaN()
+	}
+
+	var sum, lower, below float64
+	for i, upper := range h.Bounds {
+		mid := (lower + upper) / 2
+		if math.IsInf(upper, 1) {
+			mid = lower
+		}
+		sum += mid * (h.Counts[i] - below)
+		lower, below = upper, h.Counts[i]
+	}
+
+	return sum / total
+}
@@ -0,0 +1,55 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+
+	xs := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	var s Summary
+	for _, v := range xs {
+		s.Count++
+		s.Sum += v
+		s.SumSq += v * v
+	}
+
+	if s.Mean() != 5 {
+		t.Errorf("Mean=%f, wanted 5", s.Mean())
+	}
+
+	if want := math.Sqrt(32.0 / 7); math.Abs(s.Stddev()-want) > 1e-12 {
+		t.Errorf("Stddev=%f, wanted %f", s.Stddev(), want)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+
+	h := Histogram{
+		Bounds: []float64{10, 20, 50, math.Inf(1)},
+		Counts: []float64{50, 90, 99, 100},
+	}
+
+	var tests = []struct {
+		q    float64
+		want float64
+	}{
+		{0.25, 5},
+		{0.5, 10},
+		{0.7, 15},
+		{0.95, 20 + 30*5.0/9},
+		{1, 50},
+	}
+
+	for _, tt := range tests {
+		if got := h.Quantile(tt.q); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Quantile(%f)=%f, wanted %f", tt.q, got, tt.want)
+		}
+	}
+
+	if want := (50*5 + 40*15 + 9*35 + 1*50) / 100.0; math.Abs(h.Mean()-want) > 1e-9 {
+		t.Errorf("Mean=%f, wanted %f", h.Mean(), want)
+	}
+}
@@ -0,0 +1,93 @@
+package change
+
+import "time"
+
+// Suppressor decides whether a change point should be reported, for example
+// to silence predictable drops at weekends or on holidays
+type Suppressor interface {
+	// Suppress reports whether cp, detected at time t, should be dropped
+	Suppress(t time.Time, cp *ChangePoint) bool
+}
+
+// SuppressorFunc is a function used as a Suppressor
+type SuppressorFunc func(t time.Time, cp *ChangePoint) bool
+
+// Suppress calls f(t, cp)
+func (f SuppressorFunc) Suppress(t time.Time, cp *ChangePoint) bool { return f(t, cp) }
+
+// Suppressors suppresses a change point if any of its elements do
+type Suppressors []Suppressor
+
+// Suppress reports whether any of the suppressors suppresses cp
+func (s Suppressors) Suppress(t time.Time, cp *ChangePoint) bool {
+	for _, sup := range s {
+		if sup.Suppress(t, cp) {
+			return true
+		}
+	}
+	return false
+}
+
+const week = 7 * 24 * time.Hour
+
+// WeeklyWindow is a period that recurs every week, starting at Start after
+// midnight on Day.  It may run into the following days.
+type WeeklyWindow struct {
+	Day      time.Weekday
+	Start    time.Duration
+	Duration time.Duration
+}
+
+// Weekly suppresses change points detected during recurring weekly windows
+type Weekly struct {
+	Windows []WeeklyWindow
+
+	// Location is the time zone the windows are in.  If nil, the location
+	// of the detection time is used.
+	Location *time.Location
+}
+
+// Weekends returns a Weekly suppressing change points from midnight on
+// Saturday until midnight on Monday in loc
+func Weekends(loc *time.Location) *Weekly {
+	return &Weekly{
+		Windows:  []WeeklyWindow{{Day: time.Saturday, Duration: 48 * time.Hour}},
+		Location: loc,
+	}
+}
+
+// Suppress reports whether t is within one of the weekly windows
+func (w *Weekly) Suppress(t time.Time, cp *ChangePoint) bool {
+	if w.Location != nil {
+		t = t.In(w.Location)
+	}
+
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	offset := time.Duration(t.Weekday())*24*time.Hour + t.Sub(midnight)
+
+	for _, win := range w.Windows {
+		start := time.Duration(win.Day)*24*time.Hour + win.Start
+		since := ((offset-start)%week + week) % week
+		if since < win.Duration {
+			return true
+		}
+	}
+	return false
+}
+
+// Holidays suppresses change points detected on any of its dates.  Each
+// date is a whole calendar day in its own location.
+type Holidays []time.Time
+
+// Suppress reports whether t falls on one of the holidays
+func (h Holidays) Suppress(t time.Time, cp *ChangePoint) bool {
+	for _, day := range h {
+		y, m, d := t.In(day.Location()).Date()
+		hy, hm, hd := day.Date()
+		if y == hy && m == hm && d == hd {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package change
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekly(t *testing.T) {
+
+	w := Weekends(time.UTC)
+	w.Windows = append(w.Windows, WeeklyWindow{Day: time.Wednesday, Start: 23 * time.Hour, Duration: 2 * time.Hour})
+
+	var tests = []struct {
+		t    string
+		want bool
+	}{
+		{"2024-01-05T23:59:00Z", false}, // Friday
+		{"2024-01-06T00:00:00Z", true},  // Saturday
+		{"2024-01-07T23:59:00Z", true},  // Sunday
+		{"2024-01-08T00:00:00Z", false}, // Monday
+		{"2024-01-03T22:59:00Z", false}, // Wednesday
+		{"2024-01-03T23:30:00Z", true},
+		{"2024-01-04T00:30:00Z", true}, // into Thursday
+		{"2024-01-04T01:00:00Z", false},
+		{"2024-01-06T05:00:00+09:00", false}, // Friday in UTC
+	}
+
+	for _, tt := range tests {
+		ts, _ := time.Parse(time.RFC3339, tt.t)
+		if got := w.Suppress(ts, nil); got != tt.want {
+			t.Errorf("Suppress(%s)=%v, wanted %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestHolidays(t *testing.T) {
+
+	h := Holidays{time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+
+	var tests = []struct {
+		t    time.Time
+		want bool
+	}{
+		{time.Date(2024, 12, 25, 18, 0, 0, 0, time.UTC), true},
+		{time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 12, 25, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2024, 12, 25, 3, 0, 0, 0, time.FixedZone("PST", -8*3600)), true}, // 11:00 UTC
+	}
+
+	for _, tt := range tests {
+		if got := h.Suppress(tt.t, nil); got != tt.want {
+			t.Errorf("Suppress(%v)=%v, wanted %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestStreamSuppressor(t *testing.T) {
+
+	// Saturday
+	now := time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)
+
+	var consulted int
+	s := NewStream(40, 10, 10, 0.999)
+	s.now = func() time.Time { return now }
+	s.Suppressor = Suppressors{
+		Weekends(time.UTC),
+		SuppressorFunc(func(t time.Time, cp *ChangePoint) bool { consulted++; return false }),
+	}
+
+	var found int
+	for i := 0; i < 200; i++ {
+		if i == 100 {
+			// Monday
+			now = now.Add(48 * time.Hour)
+		}
+
+		v := 0.0
+		if i%100 >= 50 {
+			v = 10
+		}
+		if s.Push(v+float64(i%3)/10) != nil {
+			found++
+			if i < 100 {
+				t.Errorf("change reported at item %d during the weekend", i)
+			}
+		}
+	}
+
+	if found == 0 || consulted != found {
+		t.Errorf("found=%d consulted=%d, wanted changes after the weekend checked by every suppressor", found, consulted)
+	}
+}
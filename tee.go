@@ -0,0 +1,40 @@
+package change
+
+// Profile is a named stream detector fed by a Tee
+type Profile struct {
+	Name   string
+	Stream *Stream
+}
+
+// ProfileChange is a change found by one of the profiles of a Tee
+type ProfileChange struct {
+	Profile string
+	ChangePoint
+}
+
+// Tee feeds each item pushed to several stream detectors, typically with
+// different sensitivities such as a small window for fast alerts and a large,
+// more confident one for slow drifts
+type Tee struct {
+	profiles []Profile
+}
+
+// NewTee constructs a new fan-out over the given profiles
+func NewTee(profiles ...Profile) *Tee {
+	return &Tee{profiles: append([]Profile(nil), profiles...)}
+}
+
+// Push adds a float to every profile's stream and returns the changes found,
+// in the order the profiles were given
+func (t *Tee) Push(item float64) []ProfileChange {
+	var changes []ProfileChange
+	for _, p := range t.profiles {
+		if cp := p.Stream.Push(item); cp != nil {
+			changes = append(changes, ProfileChange{p.Name, *cp})
+		}
+	}
+	return changes
+}
+
+// Profiles returns the profiles fed by the tee
+func (t *Tee) Profiles() []Profile { return t.profiles }
@@ -0,0 +1,72 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTee(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	tee := NewTee(
+		Profile{"fast", NewStream(20, 5, 1, 0.99)},
+		Profile{"slow", NewStream(200, 50, 10, 0.9999)},
+	)
+
+	first := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		v := rnd.Float64()
+		if i >= 250 {
+			v += 2
+		}
+
+		for _, c := range tee.Push(v) {
+			if _, ok := first[c.Profile]; !ok {
+				first[c.Profile] = i
+			}
+		}
+	}
+
+	fast, okFast := first["fast"]
+	slow, okSlow := first["slow"]
+	if !okFast || !okSlow {
+		t.Fatalf("Tee changes=%v, wanted both profiles to trigger", first)
+	}
+
+	if fast >= slow {
+		t.Errorf("fast profile triggered at %d, slow at %d, wanted fast first", fast, slow)
+	}
+}
+
+func TestTeeShadow(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	candidate := NewStream(60, 10, 5, 0.99)
+	candidate.Shadow = true
+
+	tee := NewTee(
+		Profile{"current", NewStream(60, 10, 5, 0.9999)},
+		Profile{"candidate", candidate},
+	)
+
+	shadow := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		v := rnd.Float64()
+		if i >= 150 {
+			v += 2
+		}
+
+		for _, c := range tee.Push(v) {
+			if c.Shadow != (c.Profile == "candidate") {
+				t.Fatalf("%s change Shadow=%v", c.Profile, c.Shadow)
+			}
+			shadow[c.Profile]++
+		}
+	}
+
+	if shadow["current"] == 0 || shadow["candidate"] == 0 {
+		t.Errorf("changes=%v, wanted both profiles to report", shadow)
+	}
+}
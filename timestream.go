@@ -0,0 +1,150 @@
+package change
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeStream monitors a stream of timestamped floats for changes.  Unlike
+// Stream, the window and block sizes are durations, so detection does not
+// depend on the rate at which items arrive.
+type TimeStream struct {
+	window time.Duration
+	block  time.Duration
+
+	times []time.Time
+	data  []float64
+
+	// evicted is the number of items that have aged out of the window
+	evicted int
+
+	start     time.Time
+	nextCheck time.Time
+
+	detector *Detector
+
+	// CaptureWindow causes a copy of the window to be included in each
+	// change point found
+	CaptureWindow bool
+
+	// Muted, if set, is called with the time of the block being checked;
+	// while it returns true no change points are reported.
+	Muted func(t time.Time) bool
+
+	mutedUntil time.Time
+
+	// Suppressor, if set, is consulted with the time of the block being
+	// checked before each change point is reported, and drops those it
+	// suppresses
+	Suppressor Suppressor
+}
+
+// NewTimeStream constructs a new timestamped stream detector.  The window
+// covers the most recent window of time, and a check is made each time a
+// block of time has passed.  It panics if window or block is not positive.
+func NewTimeStream(window, block time.Duration, minSample int, confidence float64) *TimeStream {
+	if window <= 0 || block <= 0 {
+		panic(fmt.Sprintf("change: invalid time stream window %v and block %v", window, block))
+	}
+
+	return &TimeStream{
+		window: window,
+		block:  block,
+
+		detector: &Detector{
+			MinSampleSize: minSample,
+			MinConfidence: confidence,
+		},
+	}
+}
+
+// Push adds an item observed at time t and calls the change detector if a
+// block boundary has passed.  Items must be pushed in time order; items older
+// than the most recent one are ignored.  Gaps in the data are allowed: blocks
+// with no items are skipped and items are evicted by age, not by count.
+func (s *TimeStream) Push(t time.Time, item float64) *ChangePoint {
+	return s.push(t, item, true)
+}
+
+// Prime fills the window from historical timestamped data without running the
+// change detector.  times and history must be the same length.
+func (s *TimeStream) Prime(times []time.Time, history []float64) {
+	for i, t := range times {
+		s.push(t, history[i], false)
+	}
+}
+
+func (s *TimeStream) push(t time.Time, item float64, detect bool) *ChangePoint {
+
+	if s.start.IsZero() {
+		s.start = t
+		s.nextCheck = t.Add(s.block)
+	} else if len(s.times) > 0 && t.Before(s.times[len(s.times)-1]) {
+		return nil
+	}
+
+	var cp *ChangePoint
+
+	if !t.Before(s.nextCheck) {
+		// close the block that ended at the most recent boundary before t
+		boundary := s.nextCheck.Add(t.Sub(s.nextCheck) / s.block * s.block)
+		s.nextCheck = boundary.Add(s.block)
+
+		s.evict(boundary.Add(-s.window))
+
+		if detect && boundary.Sub(s.start) >= s.window && !s.muted(boundary) {
+			cp = s.check()
+			if cp != nil && s.Suppressor != nil && s.Suppressor.Suppress(boundary, cp) {
+				cp = nil
+			}
+		}
+	}
+
+	s.times = append(s.times, t)
+	s.data = append(s.data, item)
+
+	return cp
+}
+
+// Mute suppresses change points for blocks ending before the given time.
+// Items are still added to the window.  Muting until the zero time unmutes
+// the stream.
+func (s *TimeStream) Mute(until time.Time) { s.mutedUntil = until }
+
+func (s *TimeStream) muted(t time.Time) bool {
+	if t.Before(s.mutedUntil) {
+		return true
+	}
+	return s.Muted != nil && s.Muted(t)
+}
+
+// evict removes all items older than cutoff
+func (s *TimeStream) evict(cutoff time.Time) {
+	var i int
+	for i < len(s.times) && s.times[i].Before(cutoff) {
+		i++
+	}
+
+	if i == 0 {
+		return
+	}
+
+	s.evicted += i
+	s.times = s.times[:copy(s.times, s.times[i:])]
+	s.data = s.data[:copy(s.data, s.data[i:])]
+}
+
+func (s *TimeStream) check() *ChangePoint {
+	cp := s.detector.Check(s.data)
+	if cp != nil {
+		cp.Offset = s.evicted + cp.Index
+		cp.Time = s.times[cp.Index]
+		if s.CaptureWindow {
+			cp.Window = append([]float64(nil), s.data...)
+		}
+	}
+	return cp
+}
+
+// Window returns the items in the current window and their timestamps.  These should be treated as read-only
+func (s *TimeStream) Window() ([]time.Time, []float64) { return s.times, s.data }
@@ -0,0 +1,89 @@
+package change
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeStream(t *testing.T) {
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	changeAt := start.Add(150 * time.Second)
+
+	s := NewTimeStream(time.Minute, 10*time.Second, 5, 0.95)
+
+	var r *ChangePoint
+	for i := 0; i < 300 && r == nil; i++ {
+		ts := start.Add(time.Duration(i) * time.Second)
+
+		// a gap in the data that is longer than a block
+		if ts.After(start.Add(30*time.Second)) && ts.Before(start.Add(70*time.Second)) {
+			continue
+		}
+
+		v := 1.0
+		if !ts.Before(changeAt) {
+			v = 2.0
+		}
+		r = s.Push(ts, v)
+	}
+
+	if r == nil {
+		t.Fatalf("TimeStream failed to detect change")
+	}
+
+	if !r.Time.Equal(changeAt) {
+		t.Errorf("TimeStream change time=%v, wanted %v", r.Time, changeAt)
+	}
+
+	times, data := s.Window()
+	if len(times) != len(data) || times[0].Before(times[len(times)-1].Add(-time.Minute)) {
+		t.Errorf("TimeStream window spans %v to %v, wanted at most a minute", times[0], times[len(times)-1])
+	}
+}
+
+func TestTimeStreamPrime(t *testing.T) {
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var times []time.Time
+	var history []float64
+	for i := 0; i < 120; i++ {
+		times = append(times, start.Add(time.Duration(i)*time.Second))
+		history = append(history, 1)
+	}
+
+	s := NewTimeStream(time.Minute, 10*time.Second, 5, 0.95)
+	s.Prime(times, history)
+
+	var r *ChangePoint
+	for i := 120; i < 140 && r == nil; i++ {
+		r = s.Push(start.Add(time.Duration(i)*time.Second), 2)
+	}
+
+	if r == nil || r.Offset != 120 {
+		t.Errorf("TimeStream after Prime returned %v, wanted change at 120", r)
+	}
+}
+
+func TestTimeStreamGap(t *testing.T) {
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// a gap of years between items one second apart, with nanosecond
+	// blocks, takes one step and not one per block
+	s := NewTimeStream(time.Second, time.Nanosecond, 2, 0.95)
+	s.Push(start, 1)
+	s.Push(start.Add(500*24*time.Hour+7), 1)
+
+	if want := start.Add(500*24*time.Hour + 8); !s.nextCheck.Equal(want) {
+		t.Errorf("next check at %v, wanted %v", s.nextCheck, want)
+	}
+
+	s = NewTimeStream(time.Minute, 10*time.Second, 2, 0.95)
+	s.Push(start, 1)
+	s.Push(start.Add(95*time.Second), 1)
+	if want := start.Add(100 * time.Second); !s.nextCheck.Equal(want) {
+		t.Errorf("next check at %v, wanted %v", s.nextCheck, want)
+	}
+}
@@ -0,0 +1,37 @@
+package change
+
+import "math"
+
+// Log is a logarithmic transform suitable for Stream.Transform.  Level shifts
+// in multiplicative data such as latencies or counters become proportional
+// changes, so they are detected uniformly regardless of the absolute level.
+// To handle zeros and negative values it computes sign(x)*log(1+|x|), which
+// is close to log|x| only for |x| well above 1: below 1 it is nearly linear,
+// so changes in sub-unit data are not proportional.  Scale such data up first,
+// for example by measuring latencies in milliseconds rather than seconds, or
+// use math.Log if every value is positive.
+func Log(x float64) float64 {
+	if x < 0 {
+		return -math.Log1p(-x)
+	}
+	return math.Log1p(x)
+}
+
+// BoxCox returns a Box-Cox power transform with parameter lambda, suitable
+// for Stream.Transform.  The transform is applied to 1+|x| and the sign of x
+// is restored afterwards, so zeros and negative values are handled safely.  A
+// lambda of 0 is the same as Log, and shares its limit for values below 1.
+func BoxCox(lambda float64) func(float64) float64 {
+	return func(x float64) float64 {
+		if lambda == 0 {
+			return Log(x)
+		}
+
+		sign := 1.0
+		if x < 0 {
+			sign, x = -1, -x
+		}
+
+		return sign * (math.Pow(1+x, lambda) - 1) / lambda
+	}
+}
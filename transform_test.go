@@ -0,0 +1,81 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoxCox(t *testing.T) {
+
+	var tests = []struct {
+		lambda float64
+		x      float64
+		want   float64
+	}{
+		{0, 0, 0},
+		{0, math.E - 1, 1},
+		{0, -(math.E - 1), -1},
+		{1, 3, 3},
+		{0.5, 3, 2},
+		{0.5, -3, -2},
+	}
+
+	for _, tt := range tests {
+		if got := BoxCox(tt.lambda)(tt.x); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("BoxCox(%f)(%f)=%f, wanted %f", tt.lambda, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestStreamTransform(t *testing.T) {
+
+	// a 10% increase at two very different levels is the same change after a log transform
+	for _, level := range []float64{10, 100000} {
+		s := NewStream(40, 5, 5, 0.95)
+		s.Transform = Log
+
+		var r *ChangePoint
+		for i := 0; i < 100 && r == nil; i++ {
+			v := level * (1 + 0.01*float64(i%2))
+			if i >= 60 {
+				v *= 1.1
+			}
+			r = s.Push(v)
+		}
+
+		if r == nil || r.Offset < 59 || r.Offset > 61 {
+			t.Errorf("Stream at level %f returned %v, wanted change at 60", level, r)
+		}
+	}
+}
+
+func TestLogSubUnit(t *testing.T) {
+
+	// below 1, Log is nearly linear, so a 10% increase is a far smaller
+	// change than it is at higher levels
+	if d := Log(0.0011) - Log(0.001); d > 0.0002 {
+		t.Errorf("Log(0.0011)-Log(0.001)=%g, wanted about 0.0001", d)
+	}
+	if d := Log(11000) - Log(10000); math.Abs(d-math.Log(1.1)) > 1e-4 {
+		t.Errorf("Log(11000)-Log(10000)=%g, wanted about %g", d, math.Log(1.1))
+	}
+
+	// math.Log is proportional at any level of positive data
+	for _, level := range []float64{0.001, 0.5} {
+		s := NewStream(40, 5, 5, 0.95)
+		s.Transform = math.Log
+
+		var r *ChangePoint
+		for i := 0; i < 100 && r == nil; i++ {
+			v := level * (1 + 0.01*float64(i%2))
+			if i >= 60 {
+				v *= 1.1
+			}
+			r = s.Push(v)
+		}
+
+		if r == nil || r.Offset < 59 || r.Offset > 61 {
+			t.Errorf("Stream with math.Log at level %f returned %v, wanted change at 60", level, r)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package trend implements trend tests for time series
+package trend
+
+import (
+	"math"
+	"sort"
+)
+
+// MannKendall returns Kendall's tau and the two-sided p-value of the
+// Mann-Kendall trend test on series, using the normal approximation with a
+// correction for tied values.  A small p-value indicates a monotonic trend,
+// increasing if tau is positive and decreasing if it is negative.
+func MannKendall(series []float64) (float64, float64) {
+	n := len(series)
+	if n < 2 {
+		return 0, 1
+	}
+
+	var s float64
+	for i := 0; i < n-1; i++ {
+		for j := i + 1; j < n; j++ {
+			switch {
+			case series[j] > series[i]:
+				s++
+			case series[j] < series[i]:
+				s--
+			}
+		}
+	}
+
+	fn := float64(n)
+	variance := fn * (fn - 1) * (2*fn + 5)
+
+	// correct the variance for groups of tied values
+	sorted := append([]float64(nil), series...)
+	sort.Float64s(sorted)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && sorted[j] == sorted[i] {
+			j++
+		}
+		t := float64(j - i)
+		variance -= t * (t - 1) * (2*t + 5)
+		i = j
+	}
+	variance /= 18
+
+	tau := s / (fn * (fn - 1) / 2)
+
+	if variance <= 0 {
+		return tau, 1
+	}
+
+	var z float64
+	switch {
+	case s > 0:
+		z = (s - 1) / math.Sqrt(variance)
+	case s < 0:
+		z = (s + 1) / math.Sqrt(variance)
+	}
+
+	return tau, math.Erfc(math.Abs(z) / math.Sqrt2)
+}
@@ -0,0 +1,21 @@
+package trend
+
+import "testing"
+
+func TestMannKendall(t *testing.T) {
+
+	var tests = []struct {
+		xs  []float64
+		tau float64
+	}{
+		{[]float64{1, 2, 3, 4, 5}, 1},
+		{[]float64{5, 4, 3, 2, 1}, -1},
+		{[]float64{1, 1, 1, 1}, 0},
+	}
+
+	for _, tt := range tests {
+		if tau, p := MannKendall(tt.xs); tau != tt.tau || p < 0 || p > 1 {
+			t.Errorf("MannKendall(%v)=%f,%f, wanted tau %f", tt.xs, tau, p, tt.tau)
+		}
+	}
+}
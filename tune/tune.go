@@ -0,0 +1,106 @@
+// Package tune searches for good change detection stream parameters
+/*
+GridSearch replays a labelled series through every combination of the
+supplied parameters and returns the configurations which are not beaten by
+any other configuration on missed changes, false positives and detection
+delay.
+*/
+package tune
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dgryski/go-change/replay"
+)
+
+// Grid is the set of values to try for each stream parameter
+type Grid struct {
+	WindowSize    []int
+	MinSampleSize []int
+	BlockSize     []int
+	MinConfidence []float64
+}
+
+// Configs returns every valid combination of the parameters in the grid.
+// Combinations rejected by change.Config.Validate, such as a window which
+// cannot hold two minimum-sized samples, are skipped.
+func (g Grid) Configs() []replay.Config {
+	var configs []replay.Config
+
+	for _, w := range g.WindowSize {
+		for _, ms := range g.MinSampleSize {
+			for _, bs := range g.BlockSize {
+				for _, c := range g.MinConfidence {
+					config := replay.Config{
+						WindowSize:    w,
+						MinSampleSize: ms,
+						BlockSize:     bs,
+						MinConfidence: c,
+					}
+					if config.Validate() != nil {
+						continue
+					}
+					configs = append(configs, config)
+				}
+			}
+		}
+	}
+
+	return configs
+}
+
+// GridSearch evaluates every configuration in the grid against the labelled
+// change points in series and returns the Pareto-best results, ordered by
+// missed changes, then false positives, then mean detection delay.
+func GridSearch(series []float64, labels []int, tolerance int, grid Grid) []replay.Result {
+
+	results := replay.Run(series, labels, tolerance, grid.Configs()...)
+
+	var front []replay.Result
+	for i := range results {
+		dominated := false
+		for j := range results {
+			if i != j && dominates(&results[j], &results[i]) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, results[i])
+		}
+	}
+
+	sort.SliceStable(front, func(i, j int) bool {
+		a, b := &front[i], &front[j]
+		if a.Missed != b.Missed {
+			return a.Missed < b.Missed
+		}
+		if a.FalsePositives != b.FalsePositives {
+			return a.FalsePositives < b.FalsePositives
+		}
+		return delay(a) < delay(b)
+	})
+
+	return front
+}
+
+// dominates reports whether a is at least as good as b on every objective and strictly better on one
+func dominates(a, b *replay.Result) bool {
+	ad, bd := delay(a), delay(b)
+
+	if a.Missed > b.Missed || a.FalsePositives > b.FalsePositives || ad > bd {
+		return false
+	}
+
+	return a.Missed < b.Missed || a.FalsePositives < b.FalsePositives || ad < bd
+}
+
+// delay returns the mean detection delay of r, which is infinite if r
+// detected nothing rather than the zero returned by MeanDelay
+func delay(r *replay.Result) float64 {
+	if r.Detected == 0 {
+		return math.Inf(1)
+	}
+	return r.MeanDelay()
+}
@@ -0,0 +1,95 @@
+package tune
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dgryski/go-change/replay"
+)
+
+func TestGridSearch(t *testing.T) {
+
+	var series []float64
+	for i := 0; i < 300; i++ {
+		v := 1.0
+		if i >= 150 {
+			v = 2.0
+		}
+		series = append(series, v)
+	}
+
+	grid := Grid{
+		WindowSize:    []int{20, 40, 80},
+		MinSampleSize: []int{5, 10, 50},
+		BlockSize:     []int{5, 10},
+		MinConfidence: []float64{0.95, 0.99},
+	}
+
+	front := GridSearch(series, []int{150}, 5, grid)
+
+	if len(front) == 0 {
+		t.Fatalf("GridSearch returned no configurations")
+	}
+
+	for _, r := range front {
+		if r.Config.WindowSize < 2*r.Config.MinSampleSize {
+			t.Errorf("GridSearch returned invalid config %+v", r.Config)
+		}
+
+		for _, o := range front {
+			if dominates(&o, &r) {
+				t.Errorf("config %+v is dominated by %+v", r.Config, o.Config)
+			}
+		}
+	}
+
+	if front[0].Missed != 0 {
+		t.Errorf("best config %+v missed %d changes", front[0].Config, front[0].Missed)
+	}
+}
+
+func TestGridSearchNothingDetected(t *testing.T) {
+
+	// the shift is too small for the smallest window to detect, so
+	// that configuration detects nothing and has no delays
+	var series []float64
+	for i := 0; i < 300; i++ {
+		v := float64(i % 2)
+		if i >= 150 {
+			v += 0.5
+		}
+		series = append(series, v)
+	}
+
+	grid := Grid{
+		WindowSize:    []int{4, 100},
+		MinSampleSize: []int{2},
+		BlockSize:     []int{1},
+		MinConfidence: []float64{0.99},
+	}
+
+	results := replay.Run(series, []int{150}, 50, grid.Configs()...)
+	var nothing *replay.Result
+	for i := range results {
+		if results[i].Detected == 0 && results[i].FalsePositives == 0 {
+			nothing = &results[i]
+		}
+	}
+	if nothing == nil {
+		t.Fatalf("no configuration in %+v detected nothing", results)
+	}
+
+	if d := delay(nothing); !math.IsInf(d, 1) {
+		t.Errorf("delay of a configuration detecting nothing=%v, wanted +Inf", d)
+	}
+
+	front := GridSearch(series, []int{150}, 50, grid)
+	if len(front) == 0 {
+		t.Fatalf("GridSearch returned no configurations")
+	}
+	for _, r := range front {
+		if r.Detected == 0 {
+			t.Errorf("GridSearch returned %+v, which detects nothing and is beaten by %+v", r.Config, front[0].Config)
+		}
+	}
+}
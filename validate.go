@@ -0,0 +1,64 @@
+package change
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWindowTooShort is returned by Validate for windows too short to hold a change point
+var ErrWindowTooShort = errors.New("change: window too short")
+
+// Validate reports whether window meets the preconditions of Check, so that
+// inputs can be verified once where they enter a system instead of being
+// silently ignored by the detector.  The errors wrap a sentinel:
+//
+//   - ErrInvalidConfig if MinConfidence is not in [0, 1)
+//   - ErrWindowTooShort if the window has fewer than twice the minimum
+//     sample size items, and so no candidate positions
+//   - ErrNonFinite if any item is NaN or infinite, which makes the
+//     statistics of the check NaN
+//
+// Check does not require a window to be valid; it finds no change point in
+// a window that is not.  A Stream with a NonFinite policy other than
+// KeepNonFinite never adds non-finite values to its window.
+func (d *Detector) Validate(window []float64) error {
+	if !(d.MinConfidence >= 0 && d.MinConfidence < 1) {
+		return fmt.Errorf("%w: minimum confidence %v must be in [0, 1)", ErrInvalidConfig, d.MinConfidence)
+	}
+
+	if ms := d.minSampleSize(); len(window) < 2*ms {
+		return fmt.Errorf("%w: %d items, need at least %d for minimum sample size %d", ErrWindowTooShort, len(window), 2*ms, ms)
+	}
+
+	for i, v := range window {
+		if !finite(v) {
+			return fmt.Errorf("%w: %v at index %d", ErrNonFinite, v, i)
+		}
+	}
+
+	return nil
+}
+
+// ValidateWeighted is Validate for CheckWeighted.  In addition, there must
+// be one weight for each item, and the weights must be finite and not
+// negative.
+func (d *Detector) ValidateWeighted(window, weights []float64) error {
+	if err := d.Validate(window); err != nil {
+		return err
+	}
+
+	if len(weights) != len(window) {
+		return fmt.Errorf("%w: %d weights for %d items", ErrInvalidConfig, len(weights), len(window))
+	}
+
+	for i, w := range weights {
+		if !finite(w) {
+			return fmt.Errorf("%w: weight %v at index %d", ErrNonFinite, w, i)
+		}
+		if w < 0 {
+			return fmt.Errorf("%w: negative weight %v at index %d", ErrInvalidConfig, w, i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package change
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+
+	series := func(n int) []float64 {
+		w := make([]float64, n)
+		for i := range w {
+			w[i] = float64(i)
+		}
+		return w
+	}
+
+	nan := series(20)
+	nan[7] = math.NaN()
+
+	inf := series(20)
+	inf[19] = math.Inf(-1)
+
+	var tests = []struct {
+		minSample  int
+		confidence float64
+		window     []float64
+		want       error
+	}{
+		{10, 0.99, series(20), nil},
+		{10, 0.99, series(19), ErrWindowTooShort},
+		{10, 0.99, nil, ErrWindowTooShort},
+		{0, 0.99, series(59), ErrWindowTooShort},
+		{0, 0.99, series(60), nil},
+		{1, 0.99, series(3), ErrWindowTooShort},
+		{1, 0.99, series(4), nil},
+		{10, 0.99, nan, ErrNonFinite},
+		{10, 0.99, inf, ErrNonFinite},
+		{10, 1, series(20), ErrInvalidConfig},
+		{10, -0.5, series(20), ErrInvalidConfig},
+		{10, math.NaN(), series(20), ErrInvalidConfig},
+	}
+
+	for _, tt := range tests {
+		d := Detector{MinSampleSize: tt.minSample, MinConfidence: tt.confidence}
+		err := d.Validate(tt.window)
+		if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+			t.Errorf("Validate(MinSampleSize=%d, MinConfidence=%v, %d items)=%v, wanted %v", tt.minSample, tt.confidence, len(tt.window), err, tt.want)
+		}
+	}
+}
+
+func TestValidateWeighted(t *testing.T) {
+
+	window := make([]float64, 20)
+	ones := func() []float64 {
+		w := make([]float64, 20)
+		for i := range w {
+			w[i] = 1
+		}
+		return w
+	}
+
+	negative := ones()
+	negative[3] = -1
+
+	nan := ones()
+	nan[4] = math.NaN()
+
+	var tests = []struct {
+		weights []float64
+		want    error
+	}{
+		{ones(), nil},
+		{ones()[:19], ErrInvalidConfig},
+		{negative, ErrInvalidConfig},
+		{nan, ErrNonFinite},
+	}
+
+	d := Detector{MinSampleSize: 10, MinConfidence: 0.99}
+	for _, tt := range tests {
+		err := d.ValidateWeighted(window, tt.weights)
+		if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+			t.Errorf("ValidateWeighted(%v)=%v, wanted %v", tt.weights, err, tt.want)
+		}
+	}
+
+	if err := d.ValidateWeighted(window[:5], ones()); !errors.Is(err, ErrWindowTooShort) {
+		t.Errorf("ValidateWeighted(short window)=%v, wanted %v", err, ErrWindowTooShort)
+	}
+}
@@ -0,0 +1,39 @@
+package change
+
+import (
+	"math"
+
+	"github.com/dgryski/go-onlinestats"
+)
+
+// unitRoundoff is the largest relative rounding error of a float64 operation
+const unitRoundoff = 0x1p-53
+
+// sumsRoundoff bounds the rounding error of a sum of squared deviations
+// computed from running sums over n items whose squares add up to sumsq
+func sumsRoundoff(n int, sumsq float64) float64 {
+	return 4 * float64(n) * unitRoundoff * sumsq
+}
+
+// zeroVariance reports whether s has no variance, up to the rounding error of
+// computing it from running sums
+func zeroVariance(s Stats) bool {
+	return s.variance <= s.roundoff
+}
+
+// welch returns the confidence of the Welch t-test that before and after have
+// different means.  If neither has any variance the test is undefined, so the
+// confidence is 1 if the means differ by more than rounding error and 0
+// otherwise.  Differences smaller than the standard deviation that rounding
+// error could hide are taken to be rounding error.
+func welch(before, after Stats) float64 {
+	if zeroVariance(before) && zeroVariance(after) {
+		tolerance := 4*unitRoundoff*math.Max(math.Abs(before.mean), math.Abs(after.mean)) +
+			math.Sqrt(math.Max(before.roundoff, after.roundoff))
+		if math.Abs(before.mean-after.mean) <= tolerance {
+			return 0
+		}
+		return 1
+	}
+	return onlinestats.Welch(before, after)
+}
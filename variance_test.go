@@ -0,0 +1,90 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestZeroVariance(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	series := func(f func(i int) float64) []float64 {
+		s := make([]float64, 100)
+		for i := range s {
+			s[i] = f(i)
+		}
+		return s
+	}
+
+	step := func(i int) float64 {
+		if i >= 60 {
+			return 0.3
+		}
+		return 0.1
+	}
+
+	var tests = []struct {
+		name   string
+		window []float64
+		want   bool // a change is found
+		zero   bool
+	}{
+		{"flat", series(func(int) float64 { return 0.1 }), false, false},
+		{"zeros", series(func(int) float64 { return 0 }), false, false},
+		{"noiseless step", series(step), true, true},
+		{"noisy step", series(func(i int) float64 { return step(i) + 0.01*rnd.NormFloat64() }), true, false},
+	}
+
+	d := Detector{MinSampleSize: 10, MinConfidence: 0.99}
+
+	for _, tt := range tests {
+		cp := d.Check(tt.window)
+		if (cp != nil) != tt.want {
+			t.Errorf("%s: Check=%v, wanted change=%v", tt.name, cp, tt.want)
+			continue
+		}
+		if cp == nil {
+			continue
+		}
+
+		if cp.ZeroVariance != tt.zero {
+			t.Errorf("%s: ZeroVariance=%v, wanted %v", tt.name, cp.ZeroVariance, tt.zero)
+		}
+		if tt.zero && (cp.Confidence != 1 || cp.Index != 60) {
+			t.Errorf("%s: Confidence=%v Index=%d, wanted 1 and 60", tt.name, cp.Confidence, cp.Index)
+		}
+	}
+
+	// noise at a high level is not mistaken for rounding error
+	offset := series(func(i int) float64 { return 1e9 + 100*rnd.NormFloat64() })
+	if cp := d.Check(offset); cp != nil {
+		t.Errorf("noise around 1e9: Check=%v, wanted no change", cp)
+	}
+	_, confidence := d.Scores(offset)
+	for i, c := range confidence {
+		if c == 1 {
+			t.Errorf("noise around 1e9: Scores confidence[%d]=1, wanted the t-test", i)
+			break
+		}
+	}
+	weights := series(func(int) float64 { return 1 })
+	if cp := d.CheckWeighted(offset, weights); cp != nil {
+		t.Errorf("noise around 1e9: CheckWeighted=%v, wanted no change", cp)
+	}
+
+	// while a noiseless step at the same level has zero variance
+	high := series(func(i int) float64 { return 1e9 + step(i) })
+	if cp := d.Check(high); cp == nil || !cp.ZeroVariance || cp.Index != 60 {
+		t.Errorf("step at 1e9: Check=%v, wanted a zero variance change at 60", cp)
+	}
+
+	// the scores of a flat series are all zero rather than NaN
+	_, confidence = d.Scores(series(func(int) float64 { return 7 }))
+	for i, c := range confidence {
+		if c != 0 {
+			t.Errorf("Scores confidence[%d]=%v for a flat series, wanted 0", i, c)
+			break
+		}
+	}
+}
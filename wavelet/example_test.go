@@ -0,0 +1,19 @@
+package wavelet_test
+
+import (
+	"fmt"
+
+	"github.com/dgryski/go-change/datasets"
+	"github.com/dgryski/go-change/wavelet"
+)
+
+func ExampleDetect() {
+	ds := datasets.Synthetic()[5] // a transient rise of 3 from 250 to 350
+
+	for _, c := range wavelet.Detect(ds.Values, 0) {
+		fmt.Printf("change at %d over %d items: %+.0f\n", c.Index, c.Scale, c.Difference)
+	}
+	// Output:
+	// change at 249 over 128 items: +3
+	// change at 350 over 64 items: -3
+}
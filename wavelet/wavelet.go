@@ -0,0 +1,172 @@
+// Package wavelet finds changes at several scales with the Haar wavelet transform
+/*
+The Haar detail coefficient at scale s and index i compares the mean of the s
+items starting at i with the mean of the s items before it.  A change in level
+gives large coefficients at every scale up to the distance to the next change,
+while a short spike only stands out at the finest scales, so the scale with the
+largest coefficient says how long lived a change is.
+
+The coefficients of all dyadic scales are computed in one pass over prefix
+sums of the series, without decimation, so every index is a candidate at every
+scale.  The noise level is estimated from the finest scale with the median
+absolute deviation, which is robust to the changes themselves.
+*/
+package wavelet
+
+import (
+	"math"
+	"sort"
+)
+
+// Change is a change found at a scale
+type Change struct {
+	// Index is the index of the first item after the change
+	Index int
+
+	// Scale is the number of items on each side of the change over which
+	// it was found
+	Scale int
+
+	// Coefficient is the Haar detail coefficient in units of the noise
+	// level; it is positive for an increase
+	Coefficient float64
+
+	// Difference is the mean of the Scale items after the change less the
+	// mean of the Scale items before it, as measured at that scale
+	Difference float64
+}
+
+// Detect returns the changes in series whose coefficient is at least threshold
+// times the noise level, in order of index.  A threshold of zero uses the
+// universal threshold sqrt(2 ln m), where m is the number of coefficients
+// over all scales.  Each change is reported once, with the scale where its
+// coefficient is largest and the index found at the finest scale.  A single
+// outlier is a change at scale 1, at its index or the one after it.
+func Detect(series []float64, threshold float64) []Change {
+
+	n := len(series)
+	if n < 2 {
+		return nil
+	}
+
+	if threshold == 0 {
+		var m int
+		for s := 1; 2*s <= n; s *= 2 {
+			m += n - 2*s + 1
+		}
+		threshold = math.Sqrt(2 * math.Log(float64(m)))
+	}
+
+	// The sums are of the items less the first, which cancels out of every
+	// coefficient, so that their rounding error depends on the spread of
+	// the series and not on its level.
+	cum := make([]float64, n+1)
+	var spread float64
+	for i, v := range series {
+		v -= series[0]
+		cum[i+1] = cum[i] + v
+		spread += math.Abs(v)
+	}
+
+	// In a noiseless series the noise level is set to the largest rounding
+	// error of the coefficients, so that only real changes are found.  A
+	// constant series has no changes at all.
+	sigma := max(noise(series), 4*float64(n)*unitRoundoff*spread)
+	if sigma == 0 {
+		return nil
+	}
+
+	var candidates []Change
+	for s := 1; 2*s <= n; s *= 2 {
+		coef := make([]float64, n+1)
+		for i := s; i <= n-s; i++ {
+			coef[i] = ((cum[i+s] - cum[i]) - (cum[i] - cum[i-s])) / math.Sqrt(float64(2*s)) / sigma
+		}
+
+		// local maxima of |coef| within s of each other
+		for i := s; i <= n-s; i++ {
+			c := math.Abs(coef[i])
+			if c < threshold {
+				continue
+			}
+
+			peak := true
+			for j := max(s, i-s); j <= min(n-s, i+s) && peak; j++ {
+				a := math.Abs(coef[j])
+				peak = a < c || (a == c && j >= i)
+			}
+			if !peak {
+				continue
+			}
+
+			candidates = append(candidates, Change{
+				Index:       i,
+				Scale:       s,
+				Coefficient: coef[i],
+				Difference:  ((cum[i+s] - cum[i]) - (cum[i] - cum[i-s])) / float64(s),
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return math.Abs(candidates[i].Coefficient) > math.Abs(candidates[j].Coefficient)
+	})
+
+	// A coarse coefficient is displaced towards the middle of the window
+	// when another change is within its scale, so a weaker candidate in the
+	// same direction within the scale of a stronger change is taken to be
+	// the same change, and its index is used if it was found at a finer
+	// scale.  Candidates within the finer of the two scales are always the
+	// same change.
+	var changes []Change
+	var at, located []int
+	for _, c := range candidates {
+		near := false
+		for i, a := range changes {
+			d := c.Index - at[i]
+			if d < 0 {
+				d = -d
+			}
+
+			sameSign := (c.Coefficient > 0) == (a.Coefficient > 0)
+			if d <= min(c.Scale, a.Scale) || (sameSign && d <= max(c.Scale, a.Scale)) {
+				if sameSign && c.Scale < located[i] {
+					changes[i].Index, located[i] = c.Index, c.Scale
+				}
+				near = true
+				break
+			}
+		}
+		if !near {
+			changes = append(changes, c)
+			at, located = append(at, c.Index), append(located, c.Scale)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Index < changes[j].Index })
+
+	return changes
+}
+
+// unitRoundoff is the largest relative rounding error of a float64 operation
+const unitRoundoff = 0x1p-53
+
+// noise returns the standard deviation of the noise in series, estimated
+// from the median absolute difference of neighbouring items
+func noise(series []float64) float64 {
+	d := make([]float64, len(series)-1)
+	for i := range d {
+		d[i] = math.Abs(series[i+1]-series[i]) / math.Sqrt2
+	}
+	sort.Float64s(d)
+
+	var median float64
+	if m := len(d) / 2; len(d)%2 == 1 {
+		median = d[m]
+	} else {
+		median = (d[m-1] + d[m]) / 2
+	}
+
+	// the MAD of a normal distribution is 0.6745 standard deviations
+	return median / 0.6745
+}
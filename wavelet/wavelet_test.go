@@ -0,0 +1,79 @@
+package wavelet
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	series := make([]float64, 1024)
+	for i := range series {
+		series[i] = rnd.NormFloat64()
+		if i >= 300 {
+			series[i] += 3
+		}
+		if i == 700 {
+			series[i] += 15
+		}
+	}
+
+	changes := Detect(series, 0)
+	if len(changes) != 2 {
+		t.Fatalf("Detect=%+v, wanted 2 changes", changes)
+	}
+
+	// the step is found at a coarse scale
+	if c := changes[0]; c.Index != 300 || c.Scale < 64 || c.Coefficient <= 0 || math.Abs(c.Difference-3) > 0.5 {
+		t.Errorf("change 0=%+v, wanted an increase of about 3 at 300 with scale >= 64", c)
+	}
+
+	// the outlier is found at the finest scale
+	if c := changes[1]; (c.Index != 700 && c.Index != 701) || c.Scale != 1 {
+		t.Errorf("change 1=%+v, wanted index 700 or 701 at scale 1", c)
+	}
+}
+
+func TestDetectNoise(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(2))
+
+	series := make([]float64, 1000)
+	for i := range series {
+		series[i] = 10 + rnd.NormFloat64()
+	}
+
+	if changes := Detect(series, 0); len(changes) != 0 {
+		t.Errorf("Detect=%+v, wanted no changes in noise", changes)
+	}
+}
+
+func TestDetectNoiseless(t *testing.T) {
+
+	constant := make([]float64, 64)
+	for i := range constant {
+		constant[i] = 0.1
+	}
+
+	if changes := Detect(constant, 0); len(changes) != 0 {
+		t.Errorf("Detect(constant)=%+v, wanted no changes", changes)
+	}
+
+	for _, level := range []float64{0, 0.1, 1e9} {
+		step := make([]float64, 64)
+		for i := range step {
+			step[i] = level
+			if i >= 32 {
+				step[i] += 0.3
+			}
+		}
+
+		changes := Detect(step, 0)
+		if len(changes) != 1 || changes[0].Index != 32 || math.Abs(changes[0].Difference-0.3) > 1e-6 {
+			t.Errorf("Detect(step at %v)=%+v, wanted one change of 0.3 at 32", level, changes)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package change
+
+import "math"
+
+// CheckWeighted is like Check, but each item in the window has a weight.
+// The means and variances on each side of the change point are weighted,
+// and the Len of the Before and After statistics is the effective sample
+// size (sum of weights squared over sum of squared weights) used by the
+// t-test.  MinSampleSize still counts items.  The confidence interval for
+// the index is not computed; IndexLow and IndexHigh are both set to Index.
+func (d *Detector) CheckWeighted(window, weights []float64) *ChangePoint {
+	cp, _, _ := d.checkWeighted(window, weights)
+	return cp
+}
+
+// checkWeighted is CheckWeighted, also returning the score and confidence of
+// the best split
+func (d *Detector) checkWeighted(window, weights []float64) (*ChangePoint, float64, float64) {
+
+	n := len(window)
+
+	// cumulative sums of w, w^2, w*x and w*x^2 of all elements <= i
+	cumw := make([]float64, n)
+	cumw2 := make([]float64, n)
+	cumsum := make([]float64, n)
+	cumsumsq := make([]float64, n)
+
+	// as for Check, the sums are of the items less the first
+	var shift float64
+	if n > 0 {
+		shift = window[0]
+	}
+
+	var sw, sw2, sum, sumsq float64
+	for i, v := range window {
+		v -= shift
+		w := weights[i]
+		sw += w
+		sw2 += w * w
+		sum += w * v
+		sumsq += w * v * v
+		cumw[i], cumw2[i], cumsum[i], cumsumsq[i] = sw, sw2, sum, sumsq
+	}
+	roundoff := sumsRoundoff(n, sumsq)
+
+	var maxsb float64
+	var maxsbIdx int
+
+	var before, after Stats
+
+	minSampleSize := d.minSampleSize()
+
+	for l := minSampleSize; l < (n - minSampleSize + 1); l++ {
+		lidx := l - 1
+		w1 := cumw[lidx]
+		mean1 := cumsum[lidx] / w1
+
+		w2 := sw - w1
+		sum2 := sum - cumsum[lidx]
+		mean2 := sum2 / w2
+
+		sb := ((w1 * w2) / (w1 + w2)) * (mean1 - mean2) * (mean1 - mean2)
+		if maxsb < sb {
+			maxsb = sb
+			maxsbIdx = l
+
+			// unbiased weighted variances, treating the weights as reliability weights
+			w21, w22 := cumw2[lidx], sw2-cumw2[lidx]
+			var1 := (cumsumsq[lidx] - cumsum[lidx]*cumsum[lidx]/w1) / (w1 - w21/w1)
+			var2 := ((sumsq - cumsumsq[lidx]) - sum2*sum2/w2) / (w2 - w22/w2)
+
+			before = Stats{mean: mean1 + shift, variance: var1, n: effectiveN(w1, w21), roundoff: roundoff / (w1 - w21/w1)}
+			after = Stats{mean: mean2 + shift, variance: var2, n: effectiveN(w2, w22), roundoff: roundoff / (w2 - w22/w2)}
+		}
+	}
+
+	var conf float64
+	if before.n > 1 && after.n > 1 {
+		conf = welch(before, after)
+	}
+
+	d.logCheck(n, maxsbIdx, maxsb, conf)
+
+	if conf <= d.MinConfidence {
+		return nil, maxsb, conf
+	}
+
+	return &ChangePoint{
+		Index:      maxsbIdx,
+		IndexLow:   maxsbIdx,
+		IndexHigh:  maxsbIdx,
+		Difference: after.Mean() - before.Mean(),
+		Confidence: conf,
+		Score:      maxsb,
+		Before:     before,
+		After:      after,
+		Offset:     maxsbIdx,
+		Lag:        n - maxsbIdx,
+
+		ZeroVariance: zeroVariance(before) && zeroVariance(after),
+	}, maxsb, conf
+}
+
+// effectiveN returns Kish's effective sample size for weights with the given sum and sum of squares
+func effectiveN(sum, sumsq float64) int {
+	return int(math.Round(sum * sum / sumsq))
+}
+
+// newWeightedStats computes the weighted statistics of xs, as CheckWeighted does
+func newWeightedStats(xs, weights []float64) Stats {
+	var shift float64
+	if len(xs) > 0 {
+		shift = xs[0]
+	}
+
+	var sw, sw2, sum, sumsq float64
+	for i, v := range xs {
+		v -= shift
+		w := weights[i]
+		sw += w
+		sw2 += w * w
+		sum += w * v
+		sumsq += w * v * v
+	}
+
+	denom := sw - sw2/sw
+	return Stats{
+		mean:     sum/sw + shift,
+		variance: (sumsq - sum*sum/sw) / denom,
+		n:        effectiveN(sw, sw2),
+		roundoff: sumsRoundoff(len(xs), sumsq) / denom,
+	}
+}
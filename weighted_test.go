@@ -0,0 +1,56 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCheckWeightedUniform(t *testing.T) {
+
+	w := []float64{1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 4, 5, 4, 5, 4, 5, 4, 5, 4, 5}
+	weights := make([]float64, len(w))
+	for i := range weights {
+		weights[i] = 3
+	}
+
+	detector := Detector{MinSampleSize: 5}
+
+	r1 := detector.Check(w)
+	r2 := detector.CheckWeighted(w, weights)
+
+	if r1 == nil || r2 == nil {
+		t.Fatalf("Check=%v CheckWeighted=%v, wanted changes", r1, r2)
+	}
+
+	// uniform weights must give the same answer as no weights
+	if r1.Index != r2.Index || r1.Before.Len() != r2.Before.Len() ||
+		math.Abs(r1.Before.Var()-r2.Before.Var()) > 1e-9 || math.Abs(r1.Confidence-r2.Confidence) > 1e-9 {
+		t.Errorf("CheckWeighted=%+v, wanted %+v", r2, r1)
+	}
+}
+
+func TestStreamPushWeighted(t *testing.T) {
+
+	s := NewStream(40, 5, 5, 0.95)
+
+	// low-weight outliers should not move the estimated level much
+	var r *ChangePoint
+	for i := 0; i < 100 && r == nil; i++ {
+		v, weight := 1.0+0.1*float64(i%2), 100.0
+		if i%7 == 0 {
+			v, weight = 50, 0.01
+		}
+		if i >= 60 {
+			v += 1
+		}
+		r = s.PushWeighted(v, weight)
+	}
+
+	if r == nil || r.Offset < 59 || r.Offset > 61 {
+		t.Fatalf("Stream returned %v, wanted change at about 60", r)
+	}
+
+	if math.Abs(r.Before.Mean()-1.05) > 0.05 {
+		t.Errorf("weighted mean before change=%f, wanted about 1.05", r.Before.Mean())
+	}
+}
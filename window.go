@@ -0,0 +1,33 @@
+package change
+
+import "iter"
+
+// WindowCopy returns a copy of the current data window
+func (s *Stream) WindowCopy() []float64 {
+	return s.WindowAppend(nil)
+}
+
+// WindowAppend appends the current data window to dst and returns the
+// extended slice, so a caller can reuse its own buffer
+func (s *Stream) WindowAppend(dst []float64) []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append(dst, s.data...)
+}
+
+// WindowValues returns an iterator over the current data window, oldest item
+// first, without copying it.  The stream is locked while the loop runs, so
+// the loop body must not push to the stream.
+func (s *Stream) WindowValues() iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for _, v := range s.data {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build change_safewindow
+
+package change
+
+// Window returns a copy of the current data window.  Without the
+// change_safewindow build tag it returns the window itself.
+func (s *Stream) Window() []float64 { return s.WindowCopy() }
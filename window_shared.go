@@ -0,0 +1,9 @@
+//go:build !change_safewindow
+
+package change
+
+// Window returns the current data window.  This should be treated as
+// read-only, and is overwritten by later pushes; use WindowCopy if it is read
+// concurrently with Push.  Building with the change_safewindow tag makes
+// Window return a copy.
+func (s *Stream) Window() []float64 { return s.data }
@@ -0,0 +1,39 @@
+package change
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWindowCopy(t *testing.T) {
+
+	s := NewStream(10, 2, 5, 0.999)
+	for i := 0; i < 10; i++ {
+		s.Push(float64(i))
+	}
+
+	want := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+	w := s.WindowCopy()
+	if !slices.Equal(w, want) {
+		t.Errorf("WindowCopy=%v, wanted %v", w, want)
+	}
+
+	buf := []float64{-1}
+	if got := s.WindowAppend(buf); !slices.Equal(got, append([]float64{-1}, want...)) {
+		t.Errorf("WindowAppend=%v, wanted -1 followed by %v", got, want)
+	}
+
+	if got := slices.Collect(s.WindowValues()); !slices.Equal(got, want) {
+		t.Errorf("WindowValues=%v, wanted %v", got, want)
+	}
+
+	for i := 10; i < 15; i++ {
+		s.Push(float64(i))
+	}
+
+	// the copy is not changed by later pushes
+	if !slices.Equal(w, want) {
+		t.Errorf("WindowCopy after push=%v, wanted %v", w, want)
+	}
+}